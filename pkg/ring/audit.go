@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"maps"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// EnvOp represents an environment operation recorded by [EnvAudit].
+type EnvOp string
+
+// Environment operations recorded by [EnvAudit].
+const (
+	OpLookup EnvOp = "lookup" // The [Environ.EnvLookup] call.
+	OpGet    EnvOp = "get"    // The [Environ.EnvGet] call.
+	OpSet    EnvOp = "set"    // The [Environ.EnvSet] call.
+	OpUnset  EnvOp = "unset"  // The [Environ.EnvUnset] call.
+	OpAll    EnvOp = "all"    // The [Environ.EnvAll] call.
+)
+
+// EnvAccess represents a single environment access recorded by [EnvAudit].
+type EnvAccess struct {
+	Op    EnvOp  // Operation.
+	Key   string // Variable name, empty for [OpAll].
+	Found bool   // Variable was set at the time of the read operation.
+	File  string // Call site file.
+	Line  int    // Call site line.
+}
+
+// pkgDir is the directory of this package source files, used to skip
+// package internal frames when looking for the call site.
+var pkgDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+var _ Environ = &EnvAudit{} // Compile time check.
+
+// EnvAudit is an [Environ] wrapper recording every access to the wrapped
+// environment together with its call site. It is safe for concurrent use as
+// long as the wrapped environment is.
+type EnvAudit struct {
+	env      Environ             // The audited environment.
+	provided map[string]struct{} // Variables set when the audit started.
+	log      []EnvAccess         // Recorded accesses.
+	mx       sync.Mutex          // Guards the log.
+}
+
+// NewEnvAudit returns a new [EnvAudit] wrapping the environment. Variables
+// present in the environment at the time of the call are considered
+// provided, see [EnvAudit.Unused].
+func NewEnvAudit(env Environ) *EnvAudit {
+	provided := make(map[string]struct{})
+	for key := range EnvSplit(env.EnvAll()) {
+		provided[key] = struct{}{}
+	}
+	return &EnvAudit{env: env, provided: provided}
+}
+
+// EnvLookup retrieves the value of the variable named by the key and records
+// the access.
+func (aud *EnvAudit) EnvLookup(key string) (string, bool) {
+	val, exist := aud.env.EnvLookup(key)
+	aud.record(OpLookup, key, exist)
+	return val, exist
+}
+
+// EnvGet retrieves the value of the variable named by the key and records
+// the access.
+func (aud *EnvAudit) EnvGet(key string) string {
+	val, exist := aud.env.EnvLookup(key)
+	aud.record(OpGet, key, exist)
+	return val
+}
+
+// EnvSet sets the variable and records the access.
+func (aud *EnvAudit) EnvSet(key, value string) {
+	aud.env.EnvSet(key, value)
+	aud.record(OpSet, key, false)
+}
+
+// EnvUnset unsets the variable and records the access.
+func (aud *EnvAudit) EnvUnset(key string) {
+	aud.env.EnvUnset(key)
+	aud.record(OpUnset, key, false)
+}
+
+// EnvAll returns all variables of the wrapped environment and records the
+// access.
+func (aud *EnvAudit) EnvAll() []string {
+	all := aud.env.EnvAll()
+	aud.record(OpAll, "", false)
+	return all
+}
+
+// Accesses returns all recorded accesses in order.
+func (aud *EnvAudit) Accesses() []EnvAccess {
+	aud.mx.Lock()
+	defer aud.mx.Unlock()
+	return slices.Clone(aud.log)
+}
+
+// Read returns sorted names of variables which were read.
+func (aud *EnvAudit) Read() []string {
+	return aud.keys(func(acc EnvAccess) bool { return isRead(acc.Op) })
+}
+
+// Missing returns sorted names of variables which were read while not set.
+func (aud *EnvAudit) Missing() []string {
+	return aud.keys(func(acc EnvAccess) bool {
+		return isRead(acc.Op) && !acc.Found
+	})
+}
+
+// Written returns sorted names of variables which were set or unset.
+func (aud *EnvAudit) Written() []string {
+	return aud.keys(func(acc EnvAccess) bool {
+		return acc.Op == OpSet || acc.Op == OpUnset
+	})
+}
+
+// Unused returns sorted names of variables which were provided but never
+// read.
+func (aud *EnvAudit) Unused() []string {
+	unused := maps.Clone(aud.provided)
+	for _, key := range aud.Read() {
+		delete(unused, key)
+	}
+	if len(unused) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(unused))
+}
+
+// Reset removes all recorded accesses.
+func (aud *EnvAudit) Reset() {
+	aud.mx.Lock()
+	defer aud.mx.Unlock()
+	aud.log = nil
+}
+
+// record records the operation with the call site of the first frame
+// outside this package.
+func (aud *EnvAudit) record(op EnvOp, key string, found bool) {
+	acc := EnvAccess{Op: op, Key: key, Found: found}
+	acc.File, acc.Line = callSite()
+	aud.mx.Lock()
+	defer aud.mx.Unlock()
+	aud.log = append(aud.log, acc)
+}
+
+// keys returns sorted, unique names of variables for accesses matching the
+// filter. Returns nil if there are none.
+func (aud *EnvAudit) keys(filter func(EnvAccess) bool) []string {
+	aud.mx.Lock()
+	defer aud.mx.Unlock()
+	set := make(map[string]struct{})
+	for _, acc := range aud.log {
+		if acc.Key != "" && filter(acc) {
+			set[acc.Key] = struct{}{}
+		}
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(set))
+}
+
+// isRead returns true for read operations.
+func isRead(op EnvOp) bool { return op == OpLookup || op == OpGet }
+
+// callSite returns file and line of the first caller outside this package.
+// Test files of this package are not considered part of it.
+func callSite() (string, int) {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		internal := filepath.Dir(frame.File) == pkgDir &&
+			!strings.HasSuffix(frame.File, "_test.go")
+		if !internal {
+			return frame.File, frame.Line
+		}
+		if !more {
+			return "", 0
+		}
+	}
+}
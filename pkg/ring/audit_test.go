@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+func Test_NewEnvAudit(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"A=1", "B=2"})
+
+	// --- When ---
+	have := NewEnvAudit(env)
+
+	// --- Then ---
+	assert.Same(t, env, have.env)
+	assert.Equal(t, map[string]struct{}{"A": {}, "B": {}}, have.provided)
+	assert.Nil(t, have.log)
+}
+
+func Test_EnvAudit_EnvLookup(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		// --- Given ---
+		aud := NewEnvAudit(NewEnv([]string{"A=1"}))
+
+		// --- When ---
+		have, exist := aud.EnvLookup("A")
+
+		// --- Then ---
+		assert.True(t, exist)
+		assert.Equal(t, "1", have)
+		accs := aud.Accesses()
+		assert.Len(t, 1, accs)
+		assert.Equal(t, OpLookup, accs[0].Op)
+		assert.Equal(t, "A", accs[0].Key)
+		assert.True(t, accs[0].Found)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		// --- Given ---
+		aud := NewEnvAudit(NewEnv(nil))
+
+		// --- When ---
+		have, exist := aud.EnvLookup("A")
+
+		// --- Then ---
+		assert.False(t, exist)
+		assert.Equal(t, "", have)
+		accs := aud.Accesses()
+		assert.Len(t, 1, accs)
+		assert.False(t, accs[0].Found)
+	})
+}
+
+func Test_EnvAudit_EnvGet(t *testing.T) {
+	// --- Given ---
+	aud := NewEnvAudit(NewEnv([]string{"A=1"}))
+
+	// --- When ---
+	have := aud.EnvGet("A")
+
+	// --- Then ---
+	assert.Equal(t, "1", have)
+	accs := aud.Accesses()
+	assert.Len(t, 1, accs)
+	assert.Equal(t, OpGet, accs[0].Op)
+	assert.Equal(t, "A", accs[0].Key)
+	assert.True(t, accs[0].Found)
+}
+
+func Test_EnvAudit_EnvSet(t *testing.T) {
+	// --- Given ---
+	env := NewEnv(nil)
+	aud := NewEnvAudit(env)
+
+	// --- When ---
+	aud.EnvSet("A", "1")
+
+	// --- Then ---
+	assert.Equal(t, "1", env.EnvGet("A"))
+	accs := aud.Accesses()
+	assert.Len(t, 1, accs)
+	assert.Equal(t, OpSet, accs[0].Op)
+	assert.Equal(t, "A", accs[0].Key)
+}
+
+func Test_EnvAudit_EnvUnset(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"A=1"})
+	aud := NewEnvAudit(env)
+
+	// --- When ---
+	aud.EnvUnset("A")
+
+	// --- Then ---
+	assert.Nil(t, env.EnvAll())
+	accs := aud.Accesses()
+	assert.Len(t, 1, accs)
+	assert.Equal(t, OpUnset, accs[0].Op)
+	assert.Equal(t, "A", accs[0].Key)
+}
+
+func Test_EnvAudit_EnvAll(t *testing.T) {
+	// --- Given ---
+	aud := NewEnvAudit(NewEnv([]string{"A=1"}))
+
+	// --- When ---
+	have := aud.EnvAll()
+
+	// --- Then ---
+	assert.Equal(t, []string{"A=1"}, have)
+	accs := aud.Accesses()
+	assert.Len(t, 1, accs)
+	assert.Equal(t, OpAll, accs[0].Op)
+	assert.Equal(t, "", accs[0].Key)
+	assert.Nil(t, aud.Read())
+}
+
+func Test_EnvAudit_Accesses(t *testing.T) {
+	t.Run("call site", func(t *testing.T) {
+		// --- Given ---
+		aud := NewEnvAudit(NewEnv(nil))
+
+		// --- When ---
+		aud.EnvGet("A")
+
+		// --- Then ---
+		accs := aud.Accesses()
+		assert.Len(t, 1, accs)
+		assert.Equal(t, "audit_test.go", filepath.Base(accs[0].File))
+		assert.True(t, accs[0].Line > 0)
+	})
+
+	t.Run("call site through layer", func(t *testing.T) {
+		// --- Given ---
+		aud := NewEnvAudit(NewEnv(nil))
+		rng := New(WithEnvLayer(aud))
+
+		// --- When ---
+		rng.EnvGet("A")
+
+		// --- Then ---
+		accs := aud.Accesses()
+		assert.Len(t, 1, accs)
+		assert.Equal(t, OpLookup, accs[0].Op)
+		assert.Equal(t, "audit_test.go", filepath.Base(accs[0].File))
+	})
+
+	t.Run("returns a copy", func(t *testing.T) {
+		// --- Given ---
+		aud := NewEnvAudit(NewEnv(nil))
+		aud.EnvGet("A")
+
+		// --- When ---
+		have := aud.Accesses()
+
+		// --- Then ---
+		assert.NotSame(t, aud.log, have)
+	})
+}
+
+func Test_EnvAudit_reports(t *testing.T) {
+	// --- Given ---
+	aud := NewEnvAudit(NewEnv([]string{"A=1", "B=2", "C=3"}))
+
+	// --- When ---
+	aud.EnvGet("B")
+	aud.EnvLookup("X")
+	aud.EnvGet("A")
+	aud.EnvGet("B")
+	aud.EnvSet("Y", "1")
+	aud.EnvUnset("A")
+	aud.EnvGet("A")
+
+	// --- Then ---
+	assert.Equal(t, []string{"A", "B", "X"}, aud.Read())
+	assert.Equal(t, []string{"A", "X"}, aud.Missing())
+	assert.Equal(t, []string{"A", "Y"}, aud.Written())
+	assert.Equal(t, []string{"C"}, aud.Unused())
+}
+
+func Test_EnvAudit_empty_reports(t *testing.T) {
+	// --- Given ---
+	aud := NewEnvAudit(NewEnv([]string{"A=1"}))
+	aud.EnvGet("A")
+
+	// --- Then ---
+	assert.Nil(t, aud.Missing())
+	assert.Nil(t, aud.Written())
+	assert.Nil(t, aud.Unused())
+}
+
+func Test_EnvAudit_Reset(t *testing.T) {
+	// --- Given ---
+	aud := NewEnvAudit(NewEnv([]string{"A=1"}))
+	aud.EnvGet("A")
+
+	// --- When ---
+	aud.Reset()
+
+	// --- Then ---
+	assert.Nil(t, aud.Accesses())
+	assert.Equal(t, []string{"A"}, aud.Unused())
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"time"
+)
+
+// Timekeeper defines an interface for telling time, waiting and scheduling
+// events. It mirrors the functions of the [time] package so code using it
+// instead of the package can be tested with a controllable implementation.
+type Timekeeper interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+
+	// Until returns the duration until t.
+	Until(t time.Time) time.Duration
+
+	// Sleep pauses the current goroutine for at least the duration d.
+	Sleep(d time.Duration)
+
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) <-chan time.Time
+
+	// AfterFunc waits for the duration to elapse and then calls f in its own
+	// goroutine. It returns a [Timer] that can be used to cancel the call.
+	// The channel of the returned [Timer] is nil.
+	AfterFunc(d time.Duration, f func()) Timer
+
+	// NewTimer creates a new [Timer] that will send the current time on its
+	// channel after at least duration d.
+	NewTimer(d time.Duration) Timer
+
+	// NewTicker returns a new [Ticker] sending the current time on its
+	// channel with a period specified by the duration. It panics if d is not
+	// greater than zero.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer represents a single event, see [time.Timer].
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+
+	// Stop prevents the [Timer] from firing. It returns true if the call
+	// stops the timer, false if the timer has already expired or been
+	// stopped.
+	Stop() bool
+
+	// Reset changes the timer to expire after duration d. It returns true
+	// if the timer had been active, false if the timer had expired or been
+	// stopped.
+	Reset(d time.Duration) bool
+}
+
+// Ticker represents a periodic event, see [time.Ticker].
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered.
+	C() <-chan time.Time
+
+	// Stop turns off the ticker. After Stop, no more ticks will be sent.
+	Stop()
+
+	// Reset stops the ticker and resets its period to the duration d. The
+	// next tick will arrive after the new period elapses. It panics if d is
+	// not greater than zero.
+	Reset(d time.Duration)
+}
+
+// Compile time checks.
+var (
+	_ Timekeeper = Clock(nil)
+	_ Timekeeper = &systemClock{}
+)
+
+// systemClock is the default [Ring] [Timekeeper] telling time with [NowUTC].
+// Unlike other timekeepers, it is known to be backed by the system clock, so
+// [Stopwatch] may use monotonic clock readings of [time.Now] with it.
+type systemClock struct{ Clock }
+
+// sysClock is the [systemClock] instance used by the default [Ring].
+var sysClock = &systemClock{Clock: NowUTC}
+
+// Now returns the time returned by the [Clock] function.
+func (clk Clock) Now() time.Time { return clk() }
+
+// Since returns the time elapsed since t according to the [Clock] function.
+func (clk Clock) Since(t time.Time) time.Duration { return clk().Sub(t) }
+
+// Until returns the duration until t according to the [Clock] function.
+func (clk Clock) Until(t time.Time) time.Duration { return t.Sub(clk()) }
+
+// Sleep pauses the current goroutine for at least the duration d. Like the
+// rest of waiting and scheduling methods, it is backed by the [time] package
+// and does not depend on the [Clock] function.
+func (clk Clock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// After waits for the duration to elapse and then sends the current time on
+// the returned channel, see [time.After].
+func (clk Clock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// AfterFunc waits for the duration to elapse and then calls f in its own
+// goroutine, see [time.AfterFunc].
+func (clk Clock) AfterFunc(d time.Duration, f func()) Timer {
+	return &timer{tmr: time.AfterFunc(d, f)}
+}
+
+// NewTimer creates a new [Timer], see [time.NewTimer].
+func (clk Clock) NewTimer(d time.Duration) Timer {
+	return &timer{tmr: time.NewTimer(d)}
+}
+
+// NewTicker creates a new [Ticker], see [time.NewTicker].
+func (clk Clock) NewTicker(d time.Duration) Ticker {
+	return &ticker{tck: time.NewTicker(d)}
+}
+
+// timer implements [Timer] using [time.Timer].
+type timer struct{ tmr *time.Timer }
+
+func (t *timer) C() <-chan time.Time        { return t.tmr.C }
+func (t *timer) Stop() bool                 { return t.tmr.Stop() }
+func (t *timer) Reset(d time.Duration) bool { return t.tmr.Reset(d) }
+
+// ticker implements [Ticker] using [time.Ticker].
+type ticker struct{ tck *time.Ticker }
+
+func (t *ticker) C() <-chan time.Time   { return t.tck.C }
+func (t *ticker) Stop()                 { t.tck.Stop() }
+func (t *ticker) Reset(d time.Duration) { t.tck.Reset(d) }
@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+// tkNow is a [Timekeeper] returning a fixed time from its Now method.
+type tkNow struct {
+	Clock
+	now time.Time
+}
+
+func (tk *tkNow) Now() time.Time { return tk.now }
+
+// fixedClock returns a [Clock] always returning the given time.
+func fixedClock(tim time.Time) Clock {
+	return func() time.Time { return tim }
+}
+
+func Test_Clock_Now(t *testing.T) {
+	// --- Given ---
+	now := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+	clk := fixedClock(now)
+
+	// --- When ---
+	have := clk.Now()
+
+	// --- Then ---
+	assert.Equal(t, now, have)
+}
+
+func Test_Clock_Since(t *testing.T) {
+	// --- Given ---
+	now := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+	clk := fixedClock(now)
+
+	// --- When ---
+	have := clk.Since(now.Add(-time.Hour))
+
+	// --- Then ---
+	assert.Equal(t, time.Hour, have)
+}
+
+func Test_Clock_Until(t *testing.T) {
+	// --- Given ---
+	now := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+	clk := fixedClock(now)
+
+	// --- When ---
+	have := clk.Until(now.Add(time.Minute))
+
+	// --- Then ---
+	assert.Equal(t, time.Minute, have)
+}
+
+func Test_Clock_Sleep(t *testing.T) {
+	// --- Given ---
+	clk := Clock(NowUTC)
+	start := time.Now()
+
+	// --- When ---
+	clk.Sleep(10 * time.Millisecond)
+
+	// --- Then ---
+	assert.True(t, time.Since(start) >= 10*time.Millisecond)
+}
+
+func Test_Clock_After(t *testing.T) {
+	// --- Given ---
+	clk := Clock(NowUTC)
+	start := time.Now()
+
+	// --- When ---
+	have := <-clk.After(10 * time.Millisecond)
+
+	// --- Then ---
+	assert.True(t, have.Sub(start) >= 10*time.Millisecond)
+}
+
+func Test_Clock_AfterFunc(t *testing.T) {
+	t.Run("fires", func(t *testing.T) {
+		// --- Given ---
+		clk := Clock(NowUTC)
+		done := make(chan struct{})
+
+		// --- When ---
+		tmr := clk.AfterFunc(time.Millisecond, func() { close(done) })
+
+		// --- Then ---
+		<-done
+		assert.Nil(t, tmr.C())
+		assert.False(t, tmr.Stop())
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		// --- Given ---
+		clk := Clock(NowUTC)
+		var called atomic.Bool
+
+		// --- When ---
+		tmr := clk.AfterFunc(time.Hour, func() { called.Store(true) })
+
+		// --- Then ---
+		assert.True(t, tmr.Stop())
+		assert.False(t, called.Load())
+	})
+}
+
+func Test_Clock_NewTimer(t *testing.T) {
+	t.Run("fires", func(t *testing.T) {
+		// --- Given ---
+		clk := Clock(NowUTC)
+
+		// --- When ---
+		tmr := clk.NewTimer(time.Millisecond)
+
+		// --- Then ---
+		<-tmr.C()
+		assert.False(t, tmr.Stop())
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		// --- Given ---
+		clk := Clock(NowUTC)
+		tmr := clk.NewTimer(time.Hour)
+
+		// --- When ---
+		active := tmr.Reset(time.Millisecond)
+
+		// --- Then ---
+		assert.True(t, active)
+		<-tmr.C()
+	})
+}
+
+func Test_Clock_NewTicker(t *testing.T) {
+	// --- Given ---
+	clk := Clock(NowUTC)
+
+	// --- When ---
+	tck := clk.NewTicker(time.Millisecond)
+
+	// --- Then ---
+	<-tck.C()
+	tck.Reset(2 * time.Millisecond)
+	<-tck.C()
+	tck.Stop()
+}
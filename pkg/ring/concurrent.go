@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"sync"
+)
+
+// EnvConcurrent makes the environment safe for concurrent use by multiple
+// goroutines. It must be called before the environment is shared. Clones of
+// a concurrent environment are concurrent as well.
+//
+// Every method of a concurrent environment is atomic, but sequences of calls
+// are not. For a layered environment, the parent must be safe for concurrent
+// use on its own.
+func (env *Env) EnvConcurrent() {
+	if env.mx == nil {
+		env.mx = &sync.RWMutex{}
+	}
+}
+
+// lock locks the environment for writing when it is concurrent. Returns the
+// function unlocking it.
+func (env *Env) lock() func() { return lockMutex(env.mx) }
+
+// rlock locks the environment for reading when it is concurrent. Returns the
+// function unlocking it.
+func (env *Env) rlock() func() { return rlockMutex(env.mx) }
+
+// IOConcurrent makes getting and setting the standard I/O streams safe for
+// concurrent use by multiple goroutines. It must be called before the
+// instance is shared. Clones of a concurrent [IO] are concurrent as well.
+//
+// It does not make the streams themselves safe for concurrent use.
+func (ios *IO) IOConcurrent() {
+	if ios.mx == nil {
+		ios.mx = &sync.RWMutex{}
+	}
+}
+
+// newMutex returns a new mutex when the condition is true, otherwise nil.
+func newMutex(cond bool) *sync.RWMutex {
+	if cond {
+		return &sync.RWMutex{}
+	}
+	return nil
+}
+
+// lockMutex locks the mutex if it is not nil. Returns the function unlocking
+// it.
+func lockMutex(mx *sync.RWMutex) func() {
+	if mx == nil {
+		return noop
+	}
+	mx.Lock()
+	return mx.Unlock
+}
+
+// rlockMutex locks the mutex for reading if it is not nil. Returns the
+// function unlocking it.
+func rlockMutex(mx *sync.RWMutex) func() {
+	if mx == nil {
+		return noop
+	}
+	mx.RLock()
+	return mx.RUnlock
+}
+
+// noop does nothing.
+func noop() {}
@@ -0,0 +1,298 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+func Test_Env_EnvConcurrent(t *testing.T) {
+	t.Run("enable", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		env.EnvConcurrent()
+
+		// --- Then ---
+		assert.NotNil(t, env.mx)
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		env.EnvConcurrent()
+		mx := env.mx
+
+		// --- When ---
+		env.EnvConcurrent()
+
+		// --- Then ---
+		assert.Same(t, mx, env.mx)
+	})
+
+	t.Run("clone and flatten are concurrent", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+		env.EnvConcurrent()
+
+		// --- When ---
+		cln := env.EnvClone()
+		flt := env.EnvFlatten()
+
+		// --- Then ---
+		assert.NotNil(t, cln.mx)
+		assert.NotSame(t, env.mx, cln.mx)
+		assert.NotNil(t, flt.mx)
+		assert.NotSame(t, env.mx, flt.mx)
+	})
+
+	t.Run("not concurrent clone and flatten", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+
+		// --- When ---
+		cln := env.EnvClone()
+		flt := env.EnvFlatten()
+
+		// --- Then ---
+		assert.Nil(t, cln.mx)
+		assert.Nil(t, flt.mx)
+	})
+
+	t.Run("all returns a copy", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+		env.EnvConcurrent()
+
+		// --- When ---
+		have := env.vars()
+
+		// --- Then ---
+		assert.Equal(t, map[string]string{"A": "1"}, have)
+		assert.NotSame(t, env.env, have)
+	})
+
+	t.Run("unlocked after panic", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		env.EnvConcurrent()
+		env.EnvFreeze()
+
+		// --- When ---
+		assert.Panic(t, func() { env.EnvSet("A", "1") })
+
+		// --- Then ---
+		assert.True(t, env.mx.TryLock())
+	})
+}
+
+func Test_Env_concurrent_race(t *testing.T) {
+	// --- Given ---
+	parent := NewEnv([]string{"P=1"})
+	parent.EnvConcurrent()
+	env := NewEnvLayer(parent)
+	env.EnvConcurrent()
+
+	// --- When ---
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			key := "K" + strconv.Itoa(i)
+			for j := range 100 {
+				env.EnvSet(key, strconv.Itoa(j))
+				_ = env.EnvGet(key)
+				_, _ = env.EnvLookup("P")
+				_ = env.EnvAll()
+				env.EnvSetFrom(map[string]string{key: "x"})
+				env.EnvSensitive(key)
+				_ = env.EnvRedacted()
+				_ = env.EnvClone()
+				_, _ = env.EnvLayer()
+				for range env.EnvEach() {
+				}
+				parent.EnvSet("P", strconv.Itoa(j))
+				env.EnvUnset(key)
+			}
+		})
+	}
+	wg.Wait()
+
+	// --- Then ---
+	assert.Equal(t, []string{"P=99"}, env.EnvAll())
+}
+
+func Test_IO_IOConcurrent(t *testing.T) {
+	t.Run("enable", func(t *testing.T) {
+		// --- Given ---
+		ios := NewIO()
+
+		// --- When ---
+		ios.IOConcurrent()
+
+		// --- Then ---
+		assert.NotNil(t, ios.mx)
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		// --- Given ---
+		ios := NewIO()
+		ios.IOConcurrent()
+		mx := ios.mx
+
+		// --- When ---
+		ios.IOConcurrent()
+
+		// --- Then ---
+		assert.Same(t, mx, ios.mx)
+	})
+
+	t.Run("clone is concurrent", func(t *testing.T) {
+		// --- Given ---
+		ios := NewIO()
+		ios.IOConcurrent()
+
+		// --- When ---
+		have := ios.IOClone()
+
+		// --- Then ---
+		assert.NotNil(t, have.mx)
+		assert.NotSame(t, ios.mx, have.mx)
+	})
+}
+
+func Test_IO_concurrent_race(t *testing.T) {
+	// --- Given ---
+	ios := NewIO()
+	ios.IOConcurrent()
+
+	// --- When ---
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for range 100 {
+				ios.SetStdin(&bytes.Buffer{})
+				ios.SetStdout(&bytes.Buffer{})
+				ios.SetStderr(&bytes.Buffer{})
+				_ = ios.Stdin()
+				_ = ios.Stdout()
+				_ = ios.Stderr()
+				_ = ios.IOClone()
+			}
+		})
+	}
+	wg.Wait()
+
+	// --- Then ---
+	assert.NotNil(t, ios.Stdin())
+}
+
+func Test_Ring_concurrent_race(t *testing.T) {
+	// --- Given ---
+	rng := New(WithConcurrency(), WithEnv(nil))
+
+	// --- When ---
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			key := "K" + strconv.Itoa(i)
+			for j := range 100 {
+				rng.EnvSet(key, strconv.Itoa(j))
+				_ = rng.EnvGet(key)
+				rng.MetaSet(key, j)
+				_ = rng.MetaGet(key)
+				_, _ = rng.MetaLookup(key)
+				_ = rng.MetaAll()
+				_ = rng.Stdout()
+				rng.SetLocation(time.UTC)
+				_ = rng.LoadLocation(nil)
+				_ = rng.Location()
+				_ = rng.Now()
+				_ = rng.Clone()
+				_ = rng.Derive()
+				_ = rng.String()
+			}
+			rng.MetaDelete(key)
+		})
+	}
+	wg.Wait()
+
+	// --- Then ---
+	assert.Len(t, 8, rng.EnvAll())
+	assert.Len(t, 0, rng.MetaAll())
+}
+
+func Test_lockMutex(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		// --- When ---
+		unlock := lockMutex(nil)
+
+		// --- Then ---
+		assert.NotNil(t, unlock)
+		unlock()
+	})
+
+	t.Run("locks", func(t *testing.T) {
+		// --- Given ---
+		mx := &sync.RWMutex{}
+
+		// --- When ---
+		unlock := lockMutex(mx)
+
+		// --- Then ---
+		assert.False(t, mx.TryRLock())
+		unlock()
+		assert.True(t, mx.TryLock())
+	})
+}
+
+func Test_rlockMutex(t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		// --- When ---
+		unlock := rlockMutex(nil)
+
+		// --- Then ---
+		assert.NotNil(t, unlock)
+		unlock()
+	})
+
+	t.Run("locks for reading", func(t *testing.T) {
+		// --- Given ---
+		mx := &sync.RWMutex{}
+
+		// --- When ---
+		unlock := rlockMutex(mx)
+
+		// --- Then ---
+		assert.False(t, mx.TryLock())
+		assert.True(t, mx.TryRLock())
+		mx.RUnlock()
+		unlock()
+		assert.True(t, mx.TryLock())
+	})
+}
+
+func Test_newMutex(t *testing.T) {
+	t.Run("true", func(t *testing.T) {
+		// --- When ---
+		have := newMutex(true)
+
+		// --- Then ---
+		assert.NotNil(t, have)
+	})
+
+	t.Run("false", func(t *testing.T) {
+		// --- When ---
+		have := newMutex(false)
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// EnvChange represents a change of the environment variable value.
+type EnvChange struct {
+	Old string // Value before the change.
+	New string // Value after the change.
+}
+
+// EnvDiff represents differences between two environments.
+type EnvDiff struct {
+	Added   map[string]string    // Variables set only in the "to" environment.
+	Removed map[string]string    // Variables set only in the "from" one.
+	Changed map[string]EnvChange // Variables with different values.
+
+	// Reports if the variable is sensitive, may be nil.
+	sensitive func(key string) bool
+}
+
+// DiffEnv returns differences between the "from" and the "to" environments.
+// Since [Env.EnvFlatten] returns a detached copy of the environment, it may be
+// used to take a snapshot of the environment before it is modified:
+//
+//	snap := env.EnvFlatten()
+//	// Modify env.
+//	diff := DiffEnv(snap, env)
+//
+// When any of the environments reports sensitive variables (see
+// [Env.EnvSensitive]), their values are redacted by [EnvDiff.String].
+func DiffEnv(from, to Environ) EnvDiff {
+	before := EnvSplit(from.EnvAll())
+	after := EnvSplit(to.EnvAll())
+	diff := EnvDiff{
+		Added:   make(map[string]string),
+		Removed: make(map[string]string),
+		Changed: make(map[string]EnvChange),
+	}
+	for key, val := range after {
+		prev, exist := before[key]
+		switch {
+		case !exist:
+			diff.Added[key] = val
+		case prev != val:
+			diff.Changed[key] = EnvChange{Old: prev, New: val}
+		}
+	}
+	for key, val := range before {
+		if _, exist := after[key]; !exist {
+			diff.Removed[key] = val
+		}
+	}
+
+	type sensitiver interface{ EnvIsSensitive(key string) bool }
+	fromSen, _ := from.(sensitiver)
+	toSen, _ := to.(sensitiver)
+	if fromSen != nil || toSen != nil {
+		diff.sensitive = func(key string) bool {
+			return (fromSen != nil && fromSen.EnvIsSensitive(key)) ||
+				(toSen != nil && toSen.EnvIsSensitive(key))
+		}
+	}
+	return diff
+}
+
+// EnvDiff returns differences between the "from" environment and this one.
+// See [DiffEnv].
+func (env *Env) EnvDiff(from Environ) EnvDiff { return DiffEnv(from, env) }
+
+// IsEmpty returns true when there are no differences.
+func (diff EnvDiff) IsEmpty() bool {
+	return len(diff.Added) == 0 &&
+		len(diff.Removed) == 0 &&
+		len(diff.Changed) == 0
+}
+
+// Keys returns sorted names of all added, removed and changed variables.
+// Returns nil when there are no differences.
+func (diff EnvDiff) Keys() []string {
+	var keys []string
+	keys = slices.AppendSeq(keys, maps.Keys(diff.Added))
+	keys = slices.AppendSeq(keys, maps.Keys(diff.Removed))
+	keys = slices.AppendSeq(keys, maps.Keys(diff.Changed))
+	slices.Sort(keys)
+	return keys
+}
+
+// String returns differences sorted by variable name, one per line, in the
+// format:
+//
+//	+KEY=value       for added variables,
+//	-KEY=value       for removed variables,
+//	~KEY=old -> new  for changed variables.
+//
+// Values of sensitive variables are replaced with [Redacted]. Returns an
+// empty string when there are no differences.
+func (diff EnvDiff) String() string {
+	buf := &strings.Builder{}
+	for _, key := range diff.Keys() {
+		if val, ok := diff.Added[key]; ok {
+			buf.WriteString("+" + key + "=" + diff.value(key, val) + "\n")
+			continue
+		}
+		if val, ok := diff.Removed[key]; ok {
+			buf.WriteString("-" + key + "=" + diff.value(key, val) + "\n")
+			continue
+		}
+		chg := diff.Changed[key]
+		buf.WriteString("~" + key + "=" + diff.value(key, chg.Old))
+		buf.WriteString(" -> " + diff.value(key, chg.New) + "\n")
+	}
+	return buf.String()
+}
+
+// value returns the value or [Redacted] for sensitive variables.
+func (diff EnvDiff) value(key, val string) string {
+	if diff.sensitive != nil && diff.sensitive(key) {
+		return Redacted
+	}
+	return val
+}
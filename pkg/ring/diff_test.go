@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"testing"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+func Test_DiffEnv(t *testing.T) {
+	t.Run("differences", func(t *testing.T) {
+		// --- Given ---
+		old := NewEnv([]string{"A=1", "B=2", "C=3"})
+		cur := NewEnv([]string{"A=1", "B=-2", "D=4"})
+
+		// --- When ---
+		have := DiffEnv(old, cur)
+
+		// --- Then ---
+		assert.Equal(t, map[string]string{"D": "4"}, have.Added)
+		assert.Equal(t, map[string]string{"C": "3"}, have.Removed)
+		wChanged := map[string]EnvChange{"B": {Old: "2", New: "-2"}}
+		assert.Equal(t, wChanged, have.Changed)
+	})
+
+	t.Run("no differences", func(t *testing.T) {
+		// --- Given ---
+		old := NewEnv([]string{"A=1"})
+		cur := NewEnv([]string{"A=1"})
+
+		// --- When ---
+		have := DiffEnv(old, cur)
+
+		// --- Then ---
+		assert.Len(t, 0, have.Added)
+		assert.Len(t, 0, have.Removed)
+		assert.Len(t, 0, have.Changed)
+	})
+
+	t.Run("empty value is set", func(t *testing.T) {
+		// --- Given ---
+		old := NewEnv(nil)
+		cur := NewEnv([]string{"A="})
+
+		// --- When ---
+		have := DiffEnv(old, cur)
+
+		// --- Then ---
+		assert.Equal(t, map[string]string{"A": ""}, have.Added)
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnvLayer(NewEnv([]string{"A=1", "B=2"}))
+		snap := env.EnvFlatten()
+		env.EnvSet("A", "-1")
+		env.EnvUnset("B")
+
+		// --- When ---
+		have := DiffEnv(snap, env)
+
+		// --- Then ---
+		assert.Len(t, 0, have.Added)
+		assert.Equal(t, map[string]string{"B": "2"}, have.Removed)
+		wChanged := map[string]EnvChange{"A": {Old: "1", New: "-1"}}
+		assert.Equal(t, wChanged, have.Changed)
+	})
+
+	t.Run("rings", func(t *testing.T) {
+		// --- Given ---
+		rng := New(WithEnv([]string{"A=1"}))
+		cpy := rng.Derive()
+		cpy.EnvSet("B", "2")
+
+		// --- When ---
+		have := DiffEnv(rng, cpy)
+
+		// --- Then ---
+		assert.Equal(t, map[string]string{"B": "2"}, have.Added)
+	})
+
+	t.Run("not sensitive aware environments", func(t *testing.T) {
+		// --- Given ---
+		old := NewEnvAudit(NewEnv(nil))
+		cur := NewEnvAudit(NewEnv([]string{"A=1"}))
+
+		// --- When ---
+		have := DiffEnv(old, cur)
+
+		// --- Then ---
+		assert.Equal(t, map[string]string{"A": "1"}, have.Added)
+		assert.Nil(t, have.sensitive)
+	})
+
+	t.Run("sensitive from any environment", func(t *testing.T) {
+		// --- Given ---
+		old := NewEnv(nil)
+		old.EnvSensitive("A")
+		cur := NewEnv(nil)
+		cur.EnvSensitive("B")
+
+		// --- When ---
+		have := DiffEnv(old, cur)
+
+		// --- Then ---
+		assert.True(t, have.sensitive("A"))
+		assert.True(t, have.sensitive("B"))
+		assert.False(t, have.sensitive("C"))
+	})
+}
+
+func Test_Env_EnvDiff(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"A=1"})
+	snap := env.EnvFlatten()
+	env.EnvSet("B", "2")
+
+	// --- When ---
+	have := env.EnvDiff(snap)
+
+	// --- Then ---
+	assert.Equal(t, map[string]string{"B": "2"}, have.Added)
+	assert.Len(t, 0, have.Removed)
+	assert.Len(t, 0, have.Changed)
+}
+
+func Test_EnvDiff_IsEmpty(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		// --- Given ---
+		diff := DiffEnv(NewEnv([]string{"A=1"}), NewEnv([]string{"A=1"}))
+
+		// --- When ---
+		have := diff.IsEmpty()
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		// --- Given ---
+		diff := EnvDiff{}
+
+		// --- When ---
+		have := diff.IsEmpty()
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("added", func(t *testing.T) {
+		// --- Given ---
+		diff := EnvDiff{Added: map[string]string{"A": "1"}}
+
+		// --- When ---
+		have := diff.IsEmpty()
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		// --- Given ---
+		diff := EnvDiff{Removed: map[string]string{"A": "1"}}
+
+		// --- When ---
+		have := diff.IsEmpty()
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		// --- Given ---
+		diff := EnvDiff{Changed: map[string]EnvChange{"A": {}}}
+
+		// --- When ---
+		have := diff.IsEmpty()
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+}
+
+func Test_EnvDiff_Keys(t *testing.T) {
+	t.Run("keys", func(t *testing.T) {
+		// --- Given ---
+		old := NewEnv([]string{"A=1", "B=2", "C=3"})
+		cur := NewEnv([]string{"A=1", "B=-2", "D=4"})
+		diff := DiffEnv(old, cur)
+
+		// --- When ---
+		have := diff.Keys()
+
+		// --- Then ---
+		assert.Equal(t, []string{"B", "C", "D"}, have)
+	})
+
+	t.Run("no differences", func(t *testing.T) {
+		// --- Given ---
+		diff := EnvDiff{}
+
+		// --- When ---
+		have := diff.Keys()
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_EnvDiff_String(t *testing.T) {
+	t.Run("differences", func(t *testing.T) {
+		// --- Given ---
+		old := NewEnv([]string{"A=1", "B=2", "C=3"})
+		cur := NewEnv([]string{"A=1", "B=-2", "D=4"})
+		diff := DiffEnv(old, cur)
+
+		// --- When ---
+		have := diff.String()
+
+		// --- Then ---
+		assert.Equal(t, "~B=2 -> -2\n-C=3\n+D=4\n", have)
+	})
+
+	t.Run("sensitive", func(t *testing.T) {
+		// --- Given ---
+		old := NewEnv([]string{"A_TOKEN=1", "B_TOKEN=2", "C=3"})
+		cur := NewEnv([]string{"B_TOKEN=-2", "C=-3", "D_TOKEN=4"})
+		cur.EnvSensitive("*_TOKEN")
+		diff := DiffEnv(old, cur)
+
+		// --- When ---
+		have := diff.String()
+
+		// --- Then ---
+		want := "" +
+			"-A_TOKEN=[REDACTED]\n" +
+			"~B_TOKEN=[REDACTED] -> [REDACTED]\n" +
+			"~C=3 -> -3\n" +
+			"+D_TOKEN=[REDACTED]\n"
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("no differences", func(t *testing.T) {
+		// --- Given ---
+		diff := EnvDiff{}
+
+		// --- When ---
+		have := diff.String()
+
+		// --- Then ---
+		assert.Equal(t, "", have)
+	})
+}
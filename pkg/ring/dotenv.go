@@ -0,0 +1,358 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// Precedence decides how values loaded from files are merged with variables
+// already present in the environment.
+type Precedence int
+
+const (
+	// FillGaps sets only variables which are not already set. Values present
+	// in the environment, or set by previously loaded files, win.
+	FillGaps Precedence = iota
+
+	// Override sets all variables, overwriting values present in the
+	// environment or set by previously loaded files.
+	Override
+)
+
+// ParseDotenv parses dotenv formatted data from the reader and returns
+// variables it defines.
+//
+// The format supports:
+//   - "KEY=value" assignments with optional "export " prefix,
+//   - full line and inline comments starting with "#",
+//   - single-quoted values which are taken literally,
+//   - double-quoted values with "\n", "\r", "\t", "\\", "\"" and "\$" escape
+//     sequences,
+//   - quoted values spanning multiple lines,
+//   - variable references in unquoted and double-quoted values with the
+//     same syntax as [Expand].
+//
+// References are resolved against variables defined earlier in the data,
+// then using the lookup function, which may be nil. Syntax errors wrap
+// [ErrDotenv].
+func ParseDotenv(r io.Reader, lookup func(string) (string, bool)) (
+	map[string]string,
+	error,
+) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]string)
+	get := func(key string) (string, bool) {
+		if val, exist := ret[key]; exist {
+			return val, true
+		}
+		if lookup != nil {
+			return lookup(key)
+		}
+		return "", false
+	}
+	set := func(key, val string) { ret[key] = val }
+	if err = parseDotenv("", string(data), get, set); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// EnvLoadDotenv loads dotenv files with the given names from the filesystem
+// into the environment. See [ParseDotenv] for the supported syntax. The files
+// are loaded in order and the precedence decides if loaded values override
+// existing variables. References are resolved against the environment as it
+// is being built. The environment is not modified when any of the files
+// cannot be read or parsed. Returns [ErrReadOnly] for a frozen environment.
+func (env *Env) EnvLoadDotenv(
+	fsys fs.FS,
+	prec Precedence,
+	names ...string,
+) error {
+	if env.EnvFrozen() {
+		return ErrReadOnly
+	}
+	work := env.EnvClone()
+	var loaded []string
+	set := func(key, val string) {
+		if _, exist := work.EnvLookup(key); exist && prec == FillGaps {
+			return
+		}
+		work.EnvSet(key, val)
+		loaded = append(loaded, key+"="+val)
+	}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		err = parseDotenv(name, string(data), work.EnvLookup, set)
+		if err != nil {
+			return err
+		}
+	}
+	env.EnvSetWith(loaded)
+	return nil
+}
+
+// dotenvParser represents dotenv data parser state.
+type dotenvParser struct {
+	name   string                      // Source name used in errors.
+	src    string                      // The data being parsed.
+	pos    int                         // Current position in the src.
+	line   int                         // Current line number.
+	lookup func(string) (string, bool) // Resolves variable references.
+	set    func(key, val string)       // Called for each assignment.
+}
+
+// parseDotenv parses dotenv formatted src calling set for each assignment.
+func parseDotenv(
+	name, src string,
+	lookup func(string) (string, bool),
+	set func(key, val string),
+) error {
+	p := &dotenvParser{
+		name:   name,
+		src:    strings.ReplaceAll(src, "\r\n", "\n"),
+		line:   1,
+		lookup: lookup,
+		set:    set,
+	}
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.src) {
+			return nil
+		}
+		if err := p.assignment(); err != nil {
+			return err
+		}
+	}
+}
+
+// assignment parses a single "KEY=value" assignment.
+func (p *dotenvParser) assignment() error {
+	key := p.word()
+	if key == "export" && p.peek() != '=' {
+		p.skipSpace()
+		key = p.word()
+	}
+	if key == "" {
+		return p.errorf("invalid variable name")
+	}
+	p.skipSpace()
+	if p.peek() != '=' {
+		return p.errorf("missing '=' after %s", key)
+	}
+	p.pos++
+	p.skipSpace()
+
+	var val string
+	var err error
+	switch p.peek() {
+	case '\'':
+		val, err = p.singleQuoted()
+	case '"':
+		val, err = p.doubleQuoted()
+	default:
+		val, err = p.unquoted()
+	}
+	if err != nil {
+		return err
+	}
+	if err = p.endOfLine(); err != nil {
+		return err
+	}
+	p.set(key, val)
+	return nil
+}
+
+// word reads a variable name.
+func (p *dotenvParser) word() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if !isNameChar(c, p.pos == start) && (p.pos == start || c != '.') {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// singleQuoted reads a single-quoted value taken literally.
+func (p *dotenvParser) singleQuoted() (string, error) {
+	p.pos++ // Opening quote.
+	end := strings.IndexByte(p.src[p.pos:], '\'')
+	if end < 0 {
+		return "", p.errorf("unterminated single-quoted value")
+	}
+	val := p.src[p.pos : p.pos+end]
+	p.line += strings.Count(val, "\n")
+	p.pos += end + 1
+	return val, nil
+}
+
+// doubleQuoted reads a double-quoted value handling escape sequences and
+// variable references.
+func (p *dotenvParser) doubleQuoted() (string, error) {
+	p.pos++ // Opening quote.
+	var buf strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return buf.String(), nil
+
+		case '\\':
+			if p.pos+1 >= len(p.src) {
+				p.pos++
+				continue
+			}
+			p.pos++
+			switch e := p.src[p.pos]; e {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			case '\\', '"', '$':
+				buf.WriteByte(e)
+			default:
+				buf.WriteByte('\\')
+				buf.WriteByte(e)
+				if e == '\n' {
+					p.line++
+				}
+			}
+			p.pos++
+
+		case '$':
+			if err := p.expand(&buf); err != nil {
+				return "", err
+			}
+
+		default:
+			if c == '\n' {
+				p.line++
+			}
+			buf.WriteByte(c)
+			p.pos++
+		}
+	}
+	return "", p.errorf("unterminated double-quoted value")
+}
+
+// unquoted reads an unquoted value up to the end of line or an inline
+// comment. Trailing white space is removed.
+func (p *dotenvParser) unquoted() (string, error) {
+	var buf strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == '\n' {
+			break
+		}
+		if c == '#' && (p.pos == 0 || isSpace(p.src[p.pos-1])) {
+			break
+		}
+		if c == '$' {
+			if err := p.expand(&buf); err != nil {
+				return "", err
+			}
+			continue
+		}
+		buf.WriteByte(c)
+		p.pos++
+	}
+	return strings.TrimRight(buf.String(), " \t"), nil
+}
+
+// expand expands the variable reference at the current position writing the
+// result to the buffer.
+func (p *dotenvParser) expand(buf *strings.Builder) error {
+	val, n, err := expander{lookup: p.lookup}.ref(p.src[p.pos:])
+	if err != nil {
+		return p.wrap(err)
+	}
+	buf.WriteString(val)
+	p.pos += n
+	return nil
+}
+
+// endOfLine makes sure only white space or a comment follows the value.
+func (p *dotenvParser) endOfLine() error {
+	p.skipSpace()
+	if p.peek() == '#' {
+		p.skipComment()
+	}
+	if p.pos < len(p.src) && p.src[p.pos] != '\n' {
+		return p.errorf("unexpected character %q", p.src[p.pos])
+	}
+	return nil
+}
+
+// skipBlank skips white space, empty lines and comment lines.
+func (p *dotenvParser) skipBlank() {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case isSpace(c):
+			p.pos++
+		case c == '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+// skipSpace skips spaces and tabs.
+func (p *dotenvParser) skipSpace() {
+	for p.pos < len(p.src) && isSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// skipComment skips to the end of the line.
+func (p *dotenvParser) skipComment() {
+	if end := strings.IndexByte(p.src[p.pos:], '\n'); end >= 0 {
+		p.pos += end
+		return
+	}
+	p.pos = len(p.src)
+}
+
+// peek returns the byte at the current position or zero at the end of data.
+func (p *dotenvParser) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+// errorf returns an error wrapping [ErrDotenv] with source name and line.
+func (p *dotenvParser) errorf(format string, args ...any) error {
+	return p.wrap(fmt.Errorf(format, args...))
+}
+
+// wrap returns an error wrapping [ErrDotenv] and err with source name and
+// line.
+func (p *dotenvParser) wrap(err error) error {
+	if p.name == "" {
+		return fmt.Errorf("%w: line %d: %w", ErrDotenv, p.line, err)
+	}
+	return fmt.Errorf("%w: %s:%d: %w", ErrDotenv, p.name, p.line, err)
+}
+
+// isSpace returns true for space and tab characters.
+func isSpace(c byte) bool { return c == ' ' || c == '\t' }
@@ -0,0 +1,266 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+// errReader is an [io.Reader] always returning an error.
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, errors.New("read") }
+
+func Test_ParseDotenv_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		src  string
+		want map[string]string
+	}{
+		{"empty", "", map[string]string{}},
+		{"only comments", "# comment\n\n  # other\n", map[string]string{}},
+		{"unquoted", "A=1\nB=two", map[string]string{"A": "1", "B": "two"}},
+		{"empty value", "A=", map[string]string{"A": ""}},
+		{"spaces around", "  A = 1  ", map[string]string{"A": "1"}},
+		{"inline comment", "A=1 # comment", map[string]string{"A": "1"}},
+		{"hash in value", "A=a#b", map[string]string{"A": "a#b"}},
+		{"export prefix", "export A=1", map[string]string{"A": "1"}},
+		{"export as name", "export=1", map[string]string{"export": "1"}},
+		{"name with dot", "a.b=1", map[string]string{"a.b": "1"}},
+		{"CRLF", "A=1\r\nB=2\r\n", map[string]string{"A": "1", "B": "2"}},
+		{"single quoted", `A='a $B \n'`, map[string]string{"A": `a $B \n`}},
+		{
+			"double quoted",
+			`A="a\tb\nc \"d\" \\ \$B \x"`,
+			map[string]string{"A": "a\tb\nc \"d\" \\ $B \\x"},
+		},
+		{
+			"quoted with comment",
+			`A="a # b" # comment`,
+			map[string]string{"A": "a # b"},
+		},
+		{
+			"multi-line single",
+			"A='a\nb'\nB=2",
+			map[string]string{"A": "a\nb", "B": "2"},
+		},
+		{
+			"multi-line double",
+			"A=\"a\nb\"\nB=2",
+			map[string]string{"A": "a\nb", "B": "2"},
+		},
+		{
+			"interpolation unquoted",
+			"A=1\nB=$A-${A}",
+			map[string]string{"A": "1", "B": "1-1"},
+		},
+		{
+			"interpolation double quoted",
+			"A=1\nB=\"$A-${A}\"",
+			map[string]string{"A": "1", "B": "1-1"},
+		},
+		{
+			"interpolation from lookup",
+			"A=${LK}",
+			map[string]string{"A": "lookup"},
+		},
+		{
+			"interpolation undefined",
+			"A=${XX}$XX",
+			map[string]string{"A": ""},
+		},
+		{
+			"interpolation with default",
+			"A=${XX:-def}",
+			map[string]string{"A": "def"},
+		},
+		{
+			"lone dollar",
+			"A=$ $",
+			map[string]string{"A": "$ $"},
+		},
+		{
+			"last value counts",
+			"A=1\nA=2",
+			map[string]string{"A": "2"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- Given ---
+			lookup := NewEnv([]string{"LK=lookup"}).EnvLookup
+
+			// --- When ---
+			have, err := ParseDotenv(strings.NewReader(tc.src), lookup)
+
+			// --- Then ---
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_ParseDotenv_errors_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		src  string
+		wMsg string
+	}{
+		{
+			"invalid name",
+			"A=1\n=2",
+			"invalid dotenv syntax: line 2: invalid variable name",
+		},
+		{
+			"missing equal sign",
+			"A",
+			"invalid dotenv syntax: line 1: missing '=' after A",
+		},
+		{
+			"unterminated single quote",
+			"A='abc\n",
+			"invalid dotenv syntax: line 1: unterminated single-quoted value",
+		},
+		{
+			"unterminated double quote",
+			"A=\"abc\n\n",
+			"invalid dotenv syntax: line 3: unterminated double-quoted value",
+		},
+		{
+			"text after quoted value",
+			"\nA='a' b",
+			"invalid dotenv syntax: line 2: unexpected character 'b'",
+		},
+		{
+			"unterminated reference",
+			"A=${B",
+			"invalid dotenv syntax: line 1: bad substitution: missing '}'",
+		},
+		{
+			"invalid reference",
+			"A=${1B}",
+			"invalid dotenv syntax: line 1: bad substitution: ${1B}",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have, err := ParseDotenv(strings.NewReader(tc.src), nil)
+
+			// --- Then ---
+			assert.ErrorIs(t, ErrDotenv, err)
+			assert.ErrorEqual(t, tc.wMsg, err)
+			assert.Nil(t, have)
+		})
+	}
+}
+
+func Test_ParseDotenv(t *testing.T) {
+	t.Run("error - reading", func(t *testing.T) {
+		// --- When ---
+		have, err := ParseDotenv(errReader{}, nil)
+
+		// --- Then ---
+		assert.ErrorEqual(t, "read", err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Env_EnvLoadDotenv(t *testing.T) {
+	fsys := fstest.MapFS{
+		".env":       {Data: []byte("A=file\nB=file\nC=${A}")},
+		".env.local": {Data: []byte("B=local\nD=local")},
+		"bad.env":    {Data: []byte("A")},
+	}
+
+	t.Run("fill gaps", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=env"})
+
+		// --- When ---
+		err := env.EnvLoadDotenv(fsys, FillGaps, ".env", ".env.local")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := []string{"A=env", "B=file", "C=env", "D=local"}
+		assert.Equal(t, want, Sort(env.EnvAll()))
+	})
+
+	t.Run("override", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=env"})
+
+		// --- When ---
+		err := env.EnvLoadDotenv(fsys, Override, ".env", ".env.local")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := []string{"A=file", "B=local", "C=file", "D=local"}
+		assert.Equal(t, want, Sort(env.EnvAll()))
+	})
+
+	t.Run("case-insensitive in order", func(t *testing.T) {
+		// --- Given ---
+		fsys := fstest.MapFS{".env": {Data: []byte("Path=a\nPATH=b\npath=c")}}
+
+		for range 50 {
+			env := NewEnv(nil, WithCaseInsensitive())
+
+			// --- When ---
+			err := env.EnvLoadDotenv(fsys, Override, ".env")
+
+			// --- Then ---
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"Path=c"}, env.EnvAll())
+		}
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=env"})
+
+		// --- When ---
+		err := env.EnvLoadDotenv(fsys, Override)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"A=env"}, env.EnvAll())
+	})
+
+	t.Run("error - file does not exist", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=env"})
+
+		// --- When ---
+		err := env.EnvLoadDotenv(fsys, Override, ".env", "missing")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+		assert.Equal(t, []string{"A=env"}, env.EnvAll())
+	})
+
+	t.Run("error - syntax", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=env"})
+
+		// --- When ---
+		err := env.EnvLoadDotenv(fsys, Override, ".env", "bad.env")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrDotenv, err)
+		wMsg := "invalid dotenv syntax: bad.env:1: missing '=' after A"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Equal(t, []string{"A=env"}, env.EnvAll())
+	})
+}
@@ -4,10 +4,12 @@
 package ring
 
 import (
+	"iter"
 	"maps"
 	"os"
 	"slices"
 	"strings"
+	"sync"
 )
 
 // Environ defines an interface for managing environment variables.
@@ -31,77 +33,391 @@ type Environ interface {
 	EnvUnset(key string)
 
 	// EnvAll returns environment as a slice of "key=value" entries. It
-	// returns nil when the environment is empty.
+	// returns nil when the environment is empty. Implementations should
+	// return entries sorted by key for reproducible results.
 	EnvAll() []string
 }
 
 var _ Environ = &Env{} // Compile time check.
 
 // Env implements [Environ], storing environment variables.
-type Env struct{ env map[string]string }
+//
+// An [Env] created with [NewEnvLayer] is a layer on top of a parent
+// [Environ]. Lookups of variables not set in the layer fall through to the
+// parent, while sets and unsets are recorded only in the layer. Unsetting a
+// variable in the layer hides the parent's value.
+type Env struct {
+	env    map[string]string   // Environment variables.
+	parent Environ             // Parent environment (may be nil).
+	hidden map[string]struct{} // Variables unset in the layer.
+
+	// Sensitive variable name patterns, see [Env.EnvSensitive].
+	secrets []string
+
+	// When true, the environment cannot be modified, see [Env.EnvFreeze].
+	frozen bool
+
+	// Guards the environment, nil when not concurrent, see
+	// [Env.EnvConcurrent].
+	mx *sync.RWMutex
+
+	// Original spellings of variable names by their folded names, nil when
+	// the environment is case-sensitive, see [WithCaseInsensitive].
+	names map[string]string
+}
+
+// EnvOption configures an [Env] during creation with [NewEnv].
+type EnvOption func(*Env)
+
+// WithCaseInsensitive configures an [Env] treating variable names
+// case-insensitively, as Windows does, so "Path" and "PATH" name the same
+// variable. The environment preserves the spelling the variable was first
+// set with, which is returned by [Env.EnvAll] and other listing methods.
+// Setting the variable using a different spelling changes its value but not
+// its spelling.
+//
+// When the input passed to [NewEnv] contains the same variable spelled
+// differently, the spelling of the first entry and the value of the last
+// entry are used.
+func WithCaseInsensitive() EnvOption {
+	return func(env *Env) { env.names = make(map[string]string) }
+}
 
 // NewEnv creates a new [Env] initialized with the given environment variables.
 // If env is nil, an empty map is allocated. The input slice should contain
 // "key=value" strings, as produced by [os.Environ].
-func NewEnv(env []string) *Env {
-	if env == nil {
-		return &Env{env: make(map[string]string, 20)}
+func NewEnv(env []string, opts ...EnvOption) *Env {
+	ret := &Env{}
+	for _, opt := range opts {
+		opt(ret)
+	}
+	switch {
+	case env == nil:
+		ret.env = make(map[string]string, 20)
+	case ret.names == nil:
+		ret.env = EnvSplit(env)
+	default:
+		ret.env = make(map[string]string, len(env))
+		ret.load(env)
+	}
+	return ret
+}
+
+// NewEnvLayer creates a new empty [Env] layered on top of the parent
+// environment. Changes made to the parent are visible in the layer for
+// variables the layer does not set or unset. The layer is case-insensitive
+// when the parent is an [Env] created with [WithCaseInsensitive], also when
+// wrapped in [EnvAudit] or [EnvReadOnly]. Such a layer keeps the parent
+// spelling of variables it sets.
+func NewEnvLayer(parent Environ) *Env {
+	ret := &Env{
+		env:    make(map[string]string),
+		parent: parent,
+		hidden: make(map[string]struct{}),
 	}
-	return &Env{env: EnvSplit(env)}
+	if env := asEnv(parent); env != nil && env.names != nil {
+		ret.names = make(map[string]string)
+	}
+	return ret
 }
 
+// asEnv returns the [Env] backing the environment, looking through
+// [EnvAudit] and [EnvReadOnly] wrappers. Returns nil if there is none.
+func asEnv(env Environ) *Env {
+	for {
+		switch v := env.(type) {
+		case *Env:
+			return v
+		case *EnvAudit:
+			env = v.env
+		case *EnvReadOnly:
+			env = v.env
+		default:
+			return nil
+		}
+	}
+}
+
+// EnvCaseInsensitive returns true if the environment treats variable names
+// case-insensitively, see [WithCaseInsensitive].
+func (env *Env) EnvCaseInsensitive() bool { return env.names != nil }
+
 // EnvLookup retrieves the value of the environment variable named by the key
 // from the given env slice. Returns the value (which may be empty) and true if
 // the variable exists, or an empty string and false if it does not.
 func (env *Env) EnvLookup(key string) (string, bool) {
-	val, exist := env.env[key]
-	return val, exist
+	unlock := env.rlock()
+	val, exist := env.env[env.name(key)]
+	_, hidden := env.hidden[env.fold(key)]
+	unlock()
+	if exist {
+		return val, exist
+	}
+	if env.parent == nil || hidden {
+		return "", false
+	}
+	return env.parent.EnvLookup(key)
 }
 
 // EnvGet retrieves the value of the environment variable named by the key from
 // the given env slice. Returns the value or an empty string if not set. To
 // distinguish between an empty value and an unset value, use [Env.EnvLookup].
 func (env *Env) EnvGet(key string) string {
-	val, _ := env.env[key]
+	val, _ := env.EnvLookup(key)
 	return val
 }
 
 // EnvSet sets the environment variable named by the key to the given value.
-func (env *Env) EnvSet(key, value string) { env.env[key] = value }
+// It panics with an error wrapping [ErrReadOnly] when the environment is
+// frozen.
+func (env *Env) EnvSet(key, value string) {
+	defer env.lock()()
+	env.set(key, value)
+}
 
 // EnvSetFrom sets multiple environment variables from the given map.
 // Overwrites existing variables with the same key.
 func (env *Env) EnvSetFrom(src map[string]string) {
+	defer env.lock()()
 	for key, value := range src {
-		env.EnvSet(key, value)
+		env.set(key, value)
 	}
 }
 
 // EnvSetWith sets multiple environment variables from the given slice. The
 // input slice should contain "key=value" strings, as produced by [os.Environ].
-// Overwrites existing variables with the same key.
+// Overwrites existing variables with the same key. Entries are set in order,
+// so for a case-insensitive environment the spelling of the first entry and
+// the value of the last entry are used, see [WithCaseInsensitive].
 func (env *Env) EnvSetWith(src []string) {
-	env.EnvSetFrom(EnvSplit(src))
+	defer env.lock()()
+	env.load(src)
 }
 
-// EnvUnset unsets a single environment variable.
-func (env *Env) EnvUnset(key string) { delete(env.env, key) }
+// EnvUnset unsets a single environment variable. For a layered environment,
+// the variable is also hidden from the parent. It panics with an error
+// wrapping [ErrReadOnly] when the environment is frozen.
+func (env *Env) EnvUnset(key string) {
+	defer env.lock()()
+	env.mustWritable(OpUnset, key)
+	delete(env.env, env.name(key))
+	if env.names != nil {
+		delete(env.names, env.fold(key))
+	}
+	if env.parent != nil {
+		env.hidden[env.fold(key)] = struct{}{}
+	}
+}
+
+// set sets the environment variable. The caller must hold the write lock.
+func (env *Env) set(key, value string) {
+	env.mustWritable(OpSet, key)
+	if env.names != nil {
+		if name, exist := env.names[env.fold(key)]; exist {
+			key = name
+		} else {
+			if _, hidden := env.hidden[env.fold(key)]; !hidden {
+				if parent := asEnv(env.parent); parent != nil {
+					if name, exist := parent.spelling(key); exist {
+						key = name
+					}
+				}
+			}
+			env.names[env.fold(key)] = key
+		}
+	}
+	env.env[key] = value
+	delete(env.hidden, env.fold(key))
+}
 
-// EnvAll returns environment as a slice of "key=value" entries. It returns nil
-// when the environment is empty.
+// spelling returns the spelling of the variable named by the key in the
+// case-insensitive environment or its parents, and true if the variable is
+// set. It returns false for case-sensitive environments.
+func (env *Env) spelling(key string) (string, bool) {
+	unlock := env.rlock()
+	name, exist := env.names[env.fold(key)]
+	_, hidden := env.hidden[env.fold(key)]
+	unlock()
+	if exist || hidden || env.names == nil {
+		return name, exist
+	}
+	if parent := asEnv(env.parent); parent != nil {
+		return parent.spelling(key)
+	}
+	return "", false
+}
+
+// load sets variables from the "key=value" entries in order, skipping
+// malformed ones the same way [EnvSplit] does. The caller must hold the
+// write lock.
+func (env *Env) load(entries []string) {
+	for _, entry := range entries {
+		key, val, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			continue
+		}
+		env.set(key, val)
+	}
+}
+
+// name returns the spelling under which the variable named by the key is
+// stored. For case-sensitive environments, it returns the key.
+func (env *Env) name(key string) string {
+	if env.names == nil {
+		return key
+	}
+	if name, exist := env.names[env.fold(key)]; exist {
+		return name
+	}
+	return key
+}
+
+// fold returns the key in the form used to compare variable names. For
+// case-sensitive environments, it returns the key.
+func (env *Env) fold(key string) string {
+	if env.names == nil {
+		return key
+	}
+	return strings.ToUpper(key)
+}
+
+// EnvAll returns environment as a slice of "key=value" entries sorted by
+// key. It returns nil when the environment is empty.
 func (env *Env) EnvAll() []string {
-	if len(env.env) == 0 {
+	all := env.vars()
+	if len(all) == 0 {
 		return nil
 	}
-	ret := make([]string, 0, len(env.env))
-	for key, value := range env.env {
-		ret = append(ret, key+"="+value)
+	ret := make([]string, 0, len(all))
+	for _, key := range slices.Sorted(maps.Keys(all)) {
+		ret = append(ret, key+"="+all[key])
+	}
+	return ret
+}
+
+// EnvKeys returns an iterator over environment variable names in sorted
+// order.
+func (env *Env) EnvKeys() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for key := range env.EnvEach() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// EnvEach returns an iterator over environment variables in key order. The
+// iterator works on a snapshot of the environment taken when the iteration
+// starts, so it is safe to modify the environment while iterating.
+func (env *Env) EnvEach() iter.Seq2[string, string] {
+	return env.EnvEachPrefix("")
+}
+
+// EnvEachPrefix returns an iterator over environment variables with names
+// starting with the prefix, in key order. See [Env.EnvEach].
+func (env *Env) EnvEachPrefix(prefix string) iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		all := maps.Clone(env.vars())
+		for _, key := range slices.Sorted(maps.Keys(all)) {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			if !yield(key, all[key]) {
+				return
+			}
+		}
+	}
+}
+
+// vars returns all variables visible in the environment. For not layered
+// and not concurrent environment, it returns the underlying map, which must
+// not be modified.
+func (env *Env) vars() map[string]string {
+	if env.parent == nil {
+		defer env.rlock()()
+		if env.mx != nil {
+			return maps.Clone(env.env)
+		}
+		return env.env
+	}
+	all := EnvSplit(env.parent.EnvAll())
+	defer env.rlock()()
+	if env.names == nil {
+		for key := range env.hidden {
+			delete(all, key)
+		}
+	} else {
+		for key := range all {
+			_, hidden := env.hidden[env.fold(key)]
+			_, set := env.names[env.fold(key)]
+			if hidden || set {
+				delete(all, key)
+			}
+		}
+	}
+	maps.Copy(all, env.env)
+	return all
+}
+
+// EnvClone returns a clone of the environment. The clone of a layered
+// environment shares the parent with the original. The clone of a frozen
+// environment is not frozen, but its parent is wrapped in a read-only
+// [EnvReadOnly] view, so the parent cannot be modified through the clone.
+func (env *Env) EnvClone() *Env {
+	defer env.rlock()()
+	parent := env.parent
+	if env.frozen && parent != nil {
+		parent = NewEnvReadOnly(parent)
 	}
+	return &Env{
+		env:     maps.Clone(env.env),
+		parent:  parent,
+		hidden:  maps.Clone(env.hidden),
+		secrets: slices.Clone(env.secrets),
+		mx:      newMutex(env.mx != nil),
+		names:   maps.Clone(env.names),
+	}
+}
+
+// EnvParent returns the parent environment or nil if the environment is not
+// layered. For a frozen environment the parent is returned as a read-only
+// [EnvReadOnly] view, so it cannot be modified through the frozen layer.
+func (env *Env) EnvParent() Environ {
+	defer env.rlock()()
+	if env.frozen && env.parent != nil {
+		return NewEnvReadOnly(env.parent)
+	}
+	return env.parent
+}
+
+// EnvFlatten returns a new [Env] with all variables visible in the
+// environment, detached from the parent. Sensitive variable patterns of the
+// environment and its parents are preserved, as well as its concurrency and
+// case-insensitivity.
+func (env *Env) EnvFlatten() *Env {
+	var opts []EnvOption
+	if env.names != nil {
+		opts = append(opts, WithCaseInsensitive())
+	}
+	ret := NewEnv(env.EnvAll(), opts...)
+	ret.secrets = env.secretPatterns()
+	ret.mx = newMutex(env.mx != nil)
 	return ret
 }
 
-// EnvClone returns a clone of the environment.
-func (env *Env) EnvClone() *Env { return &Env{env: maps.Clone(env.env)} }
+// EnvLayer returns variables set in the layer and variables unset in the
+// layer, which hide the parent's values. The unset slice is sorted. For not
+// layered environment, the unset slice is always nil. For case-insensitive
+// environments, the unset variable names are upper-cased.
+func (env *Env) EnvLayer() (set map[string]string, unset []string) {
+	defer env.rlock()()
+	set = maps.Clone(env.env)
+	if len(env.hidden) > 0 {
+		unset = slices.Sorted(maps.Keys(env.hidden))
+	}
+	return set, unset
+}
 
 // EnvLookup retrieves the value of the "env" variable named by the key. If the
 // variable is present in the "env", the value (which may be empty) is returned
@@ -121,10 +437,7 @@ func EnvGet(env []string, key string) string {
 // EnvGetDefault retrieves the value of the "env" variable named by the key. If
 // the key is not present in the environment, it will return def value.
 func EnvGetDefault(env []string, key, def string) string {
-	if val, exist := EnvLookup(env, key); exist {
-		return val
-	}
-	return def
+	return NewEnv(env).EnvGetDefault(key, def)
 }
 
 // EnvSet sets a single environment variable. Returns the modified slice.
@@ -150,6 +463,8 @@ func EnvUnset(env []string, key string) []string {
 }
 
 // EnvSplit parses [os.Environ] results and returns it as a key value map.
+// Entries without "=" or with an empty key are skipped, and for duplicate
+// keys the last value wins. Use [EnvSplitStrict] to detect such entries.
 func EnvSplit(env []string) map[string]string {
 	m := make(map[string]string, 10)
 	for _, s := range env {
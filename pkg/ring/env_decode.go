@@ -0,0 +1,273 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Struct tags recognized by [EnvDecode].
+const (
+	// TagEnv is the struct tag holding the environment variable name and a
+	// comma separated list of options. The only supported option is
+	// "required". Use "-" to skip a field.
+	TagEnv = "env"
+
+	// TagEnvDefault is the struct tag holding the default value used when the
+	// environment variable is not set.
+	TagEnvDefault = "envDefault"
+
+	// TagEnvPrefix is the struct tag holding the prefix prepended to
+	// environment variable names of a nested struct.
+	TagEnvPrefix = "envPrefix"
+)
+
+// Types with special handling in [EnvDecode].
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// EnvDecode fills the struct pointed to by dst with values of environment
+// variables named by the fields' struct tags.
+//
+// Supported field types are strings, booleans, integers, unsigned integers,
+// floats, [time.Duration], types implementing [encoding.TextUnmarshaler],
+// pointers to any of them, slices of them (comma separated items) and maps of
+// them ("key:value" comma separated items). Nested structs (without the
+// "env" tag) are decoded recursively with the "envPrefix" tag value
+// prepended to their variable names. Nil pointers to nested structs are
+// allocated only when at least one of their variables is set, otherwise they
+// stay nil and errors of their fields are not reported. Nested structs
+// of a type which is already being decoded (recursive types) are skipped.
+//
+// Example:
+//
+//	type Config struct {
+//	    Port    int           `env:"PORT" envDefault:"8080"`
+//	    Token   string        `env:"TOKEN,required"`
+//	    Timeout time.Duration `env:"TIMEOUT" envDefault:"5s"`
+//	    Hosts   []string      `env:"HOSTS"`
+//	    DB      struct {
+//	        Host string `env:"HOST"` // Reads DB_HOST.
+//	    } `envPrefix:"DB_"`
+//	}
+//
+// All missing and invalid variables are reported in a single error joining
+// errors wrapping [ErrReqEnv] and [ErrInvEnv]. It returns an error wrapping
+// [ErrInvTarget] when dst is not a non-nil pointer to a struct.
+func EnvDecode(env Environ, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("%w: %T", ErrInvTarget, dst)
+	}
+	if rv = rv.Elem(); rv.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T", ErrInvTarget, dst)
+	}
+	var errs []error
+	decodeStruct(env, rv, "", map[reflect.Type]bool{}, &errs)
+	return errors.Join(errs...)
+}
+
+// decodeStruct decodes environment variables into struct fields of rv.
+// Errors are appended to errs. The seen map holds struct types currently
+// being decoded, nested fields of those types are skipped to break cycles.
+// Returns true if at least one variable was set in the environment.
+func decodeStruct(
+	env Environ,
+	rv reflect.Value,
+	prefix string,
+	seen map[reflect.Type]bool,
+	errs *[]error,
+) bool {
+	rt := rv.Type()
+	seen[rt] = true
+	defer delete(seen, rt)
+
+	var decoded bool
+	for i := range rt.NumField() {
+		sf := rt.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get(TagEnv)
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fv := rv.Field(i)
+
+		if name == "" && isNested(sf.Type) {
+			nested := prefix + sf.Tag.Get(TagEnvPrefix)
+			if fv.Kind() != reflect.Pointer {
+				decoded = decodeStruct(env, fv, nested, seen, errs) || decoded
+				continue
+			}
+			if seen[sf.Type.Elem()] {
+				continue
+			}
+			if !fv.IsNil() {
+				if decodeStruct(env, fv.Elem(), nested, seen, errs) {
+					decoded = true
+				}
+				continue
+			}
+			// Allocate nil pointers only when any of the fields is set,
+			// otherwise the section is not configured and its errors are
+			// discarded.
+			ptr := reflect.New(sf.Type.Elem())
+			var nestedErrs []error
+			if decodeStruct(env, ptr.Elem(), nested, seen, &nestedErrs) {
+				fv.Set(ptr)
+				decoded = true
+				*errs = append(*errs, nestedErrs...)
+			}
+			continue
+		}
+		if name == "" {
+			continue
+		}
+
+		key := prefix + name
+		val, exist := env.EnvLookup(key)
+		decoded = decoded || exist
+		if !exist {
+			def, hasDef := sf.Tag.Lookup(TagEnvDefault)
+			if !hasDef {
+				if hasOption(opts, "required") {
+					*errs = append(*errs, envRequired(key))
+				}
+				continue
+			}
+			val = def
+		}
+		if err := decodeValue(fv, val); err != nil {
+			*errs = append(*errs, envInvalid(key, val, err))
+		}
+	}
+	return decoded
+}
+
+// decodeValue parses val and sets it to rv.
+func decodeValue(rv reflect.Value, val string) error {
+	if rv.Kind() == reflect.Pointer {
+		ptr := reflect.New(rv.Type().Elem())
+		if err := decodeValue(ptr.Elem(), val); err != nil {
+			return err
+		}
+		rv.Set(ptr)
+		return nil
+	}
+	if rv.CanAddr() {
+		if tu, ok := rv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return tu.UnmarshalText([]byte(val))
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(val)
+
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		rv.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		if rv.Type() == durationType {
+			d, err := time.ParseDuration(val)
+			if err != nil {
+				return err
+			}
+			rv.SetInt(int64(d))
+			return nil
+		}
+		n, err := strconv.ParseInt(val, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		n, err := strconv.ParseUint(val, 10, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(val, rv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		rv.SetFloat(f)
+
+	case reflect.Slice:
+		items, _ := parseList(val)
+		lst := reflect.MakeSlice(rv.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(lst.Index(i), item); err != nil {
+				return err
+			}
+		}
+		rv.Set(lst)
+
+	case reflect.Map:
+		items, _ := parseList(val)
+		m := reflect.MakeMapWithSize(rv.Type(), len(items))
+		for _, item := range items {
+			k, v, ok := strings.Cut(item, ":")
+			if !ok {
+				return fmt.Errorf("invalid map item %q", item)
+			}
+			kv := reflect.New(rv.Type().Key()).Elem()
+			if err := decodeValue(kv, strings.TrimSpace(k)); err != nil {
+				return err
+			}
+			vv := reflect.New(rv.Type().Elem()).Elem()
+			if err := decodeValue(vv, strings.TrimSpace(v)); err != nil {
+				return err
+			}
+			m.SetMapIndex(kv, vv)
+		}
+		rv.Set(m)
+
+	default:
+		return fmt.Errorf("unsupported type %s", rv.Type())
+	}
+	return nil
+}
+
+// isNested returns true if the type is a struct, or a pointer to a struct,
+// which does not implement [encoding.TextUnmarshaler].
+func isNested(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	return !reflect.PointerTo(typ).Implements(textUnmarshalerType)
+}
+
+// hasOption returns true if the comma separated list of options contains
+// the given option.
+func hasOption(opts, option string) bool {
+	for opt := range strings.SplitSeq(opts, ",") {
+		if strings.TrimSpace(opt) == option {
+			return true
+		}
+	}
+	return false
+}
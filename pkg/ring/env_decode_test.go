@@ -0,0 +1,336 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+// level is a test type implementing [encoding.TextUnmarshaler].
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+// TDB is a test struct decoded as a nested struct.
+type TDB struct {
+	Host string `env:"HOST" envDefault:"localhost"`
+	Port int    `env:"PORT,required"`
+}
+
+// TCycleA is a test struct referencing [TCycleB] which references it back.
+type TCycleA struct {
+	Name string   `env:"NAME"`
+	B    *TCycleB `envPrefix:"B_"`
+}
+
+// TCycleB is a test struct referencing [TCycleA].
+type TCycleB struct {
+	Name string   `env:"NAME"`
+	A    *TCycleA `envPrefix:"A_"`
+}
+
+// TEmbedded is a test struct embedded in [TConfig].
+type TEmbedded struct {
+	Debug bool `env:"DEBUG"`
+}
+
+// TConfig is a test struct for [EnvDecode].
+type TConfig struct {
+	TEmbedded
+	Name     string            `env:"NAME"`
+	Int      int               `env:"INT"`
+	Int8     int8              `env:"INT8"`
+	Uint     uint              `env:"UINT"`
+	Float    float64           `env:"FLOAT"`
+	Dur      time.Duration     `env:"DUR"`
+	Level    level             `env:"LEVEL"`
+	Addr     netip.Addr        `env:"ADDR"`
+	Ptr      *int              `env:"PTR"`
+	List     []string          `env:"LIST"`
+	Ints     []int             `env:"INTS"`
+	Map      map[string]int    `env:"MAP"`
+	Def      string            `env:"DEF" envDefault:"default"`
+	DB       TDB               `envPrefix:"DB_"`
+	Cache    *TDB              `envPrefix:"CACHE_"`
+	Skip     string            `env:"-"`
+	Labels   map[string]string `env:"LABELS"`
+	Optional *string           `env:"OPTIONAL"`
+	private  string            `env:"PRIVATE"`
+	NoTag    string
+}
+
+func Test_EnvDecode(t *testing.T) {
+	t.Run("all fields", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{
+			"DEBUG=true",
+			"NAME=name",
+			"INT=-1",
+			"INT8=8",
+			"UINT=2",
+			"FLOAT=1.5",
+			"DUR=1m",
+			"LEVEL=high",
+			"ADDR=127.0.0.1",
+			"PTR=3",
+			"LIST=a, b",
+			"INTS=1,2,3",
+			"MAP=a:1,b:2",
+			"DB_HOST=db.local",
+			"DB_PORT=5432",
+			"CACHE_PORT=6379",
+			"PRIVATE=private",
+			"LABELS=k:v",
+			"NoTag=x",
+			"Skip=x",
+		})
+		cfg := TConfig{}
+
+		// --- When ---
+		err := EnvDecode(env, &cfg)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.True(t, cfg.Debug)
+		assert.Equal(t, "name", cfg.Name)
+		assert.Equal(t, -1, cfg.Int)
+		assert.Equal(t, int8(8), cfg.Int8)
+		assert.Equal(t, uint(2), cfg.Uint)
+		assert.Equal(t, 1.5, cfg.Float)
+		assert.Equal(t, time.Minute, cfg.Dur)
+		assert.Equal(t, level(2), cfg.Level)
+		assert.Equal(t, netip.MustParseAddr("127.0.0.1"), cfg.Addr)
+		assert.NotNil(t, cfg.Ptr)
+		assert.Equal(t, 3, *cfg.Ptr)
+		assert.Equal(t, []string{"a", "b"}, cfg.List)
+		assert.Equal(t, []int{1, 2, 3}, cfg.Ints)
+		assert.Equal(t, map[string]int{"a": 1, "b": 2}, cfg.Map)
+		assert.Equal(t, "default", cfg.Def)
+		assert.Equal(t, TDB{Host: "db.local", Port: 5432}, cfg.DB)
+		assert.Equal(t, &TDB{Host: "localhost", Port: 6379}, cfg.Cache)
+		assert.Equal(t, "", cfg.Skip)
+		assert.Equal(t, "", cfg.NoTag)
+		assert.Equal(t, "", cfg.private)
+		assert.Equal(t, map[string]string{"k": "v"}, cfg.Labels)
+		assert.Nil(t, cfg.Optional)
+	})
+
+	t.Run("empty value is set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"DEF=", "DB_PORT=1", "CACHE_PORT=1"})
+		cfg := TConfig{}
+
+		// --- When ---
+		err := EnvDecode(env, &cfg)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "", cfg.Def)
+	})
+
+	t.Run("keeps values of not set variables", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"DB_PORT=1", "CACHE_PORT=1"})
+		cfg := TConfig{Name: "keep"}
+
+		// --- When ---
+		err := EnvDecode(env, &cfg)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "keep", cfg.Name)
+	})
+
+	t.Run("nil nested pointer not allocated when not set", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			DB *TDB `envPrefix:"DB_"`
+		}
+		cfg := T{}
+
+		// --- When ---
+		err := EnvDecode(NewEnv(nil), &cfg)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, cfg.DB)
+	})
+
+	t.Run("error - nested pointer partially set", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			DB *TDB `envPrefix:"DB_"`
+		}
+		cfg := T{}
+
+		// --- When ---
+		err := EnvDecode(NewEnv([]string{"DB_HOST=db"}), &cfg)
+
+		// --- Then ---
+		assert.ErrorEqual(t, "required environment variable: DB_PORT", err)
+		assert.Equal(t, &TDB{Host: "db"}, cfg.DB)
+	})
+
+	t.Run("error - non-nil nested pointer", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			DB *TDB `envPrefix:"DB_"`
+		}
+		cfg := T{DB: &TDB{}}
+
+		// --- When ---
+		err := EnvDecode(NewEnv(nil), &cfg)
+
+		// --- Then ---
+		assert.ErrorEqual(t, "required environment variable: DB_PORT", err)
+		assert.Equal(t, &TDB{Host: "localhost"}, cfg.DB)
+	})
+
+	t.Run("recursive type", func(t *testing.T) {
+		// --- Given ---
+		type Node struct {
+			Name string `env:"NAME"`
+			Next *Node  `envPrefix:"NEXT_"`
+		}
+		env := NewEnv([]string{"NAME=a", "NEXT_NAME=b"})
+		cfg := Node{}
+
+		// --- When ---
+		err := EnvDecode(env, &cfg)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, Node{Name: "a"}, cfg)
+	})
+
+	t.Run("mutually recursive types", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"NAME=a", "B_NAME=b", "B_A_NAME=c"})
+		cfg := TCycleA{}
+
+		// --- When ---
+		err := EnvDecode(env, &cfg)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, TCycleA{Name: "a", B: &TCycleB{Name: "b"}}, cfg)
+	})
+
+	t.Run("error - all errors reported", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"INT=abc", "LEVEL=mid", "MAP=a"})
+		cfg := TConfig{}
+
+		// --- When ---
+		err := EnvDecode(env, &cfg)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReqEnv, err)
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "invalid environment variable: INT=\"abc\": invalid syntax\n" +
+			"invalid environment variable: LEVEL=\"mid\": unknown level\n" +
+			"invalid environment variable: MAP=\"a\": " +
+			"invalid map item \"a\"\n" +
+			"required environment variable: DB_PORT"
+		assert.ErrorEqual(t, wMsg, err)
+	})
+
+	t.Run("error - invalid default", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			Int int `env:"INT" envDefault:"abc"`
+		}
+		cfg := T{}
+
+		// --- When ---
+		err := EnvDecode(NewEnv(nil), &cfg)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+	})
+
+	t.Run("error - unsupported type", func(t *testing.T) {
+		// --- Given ---
+		type T struct {
+			Ch chan int `env:"CH"`
+		}
+		cfg := T{}
+
+		// --- When ---
+		err := EnvDecode(NewEnv([]string{"CH=1"}), &cfg)
+
+		// --- Then ---
+		wMsg := "invalid environment variable: CH=\"1\": " +
+			"unsupported type chan int"
+		assert.ErrorEqual(t, wMsg, err)
+	})
+
+	t.Run("error - not a pointer", func(t *testing.T) {
+		// --- When ---
+		err := EnvDecode(NewEnv(nil), TConfig{})
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvTarget, err)
+		assert.ErrorEqual(t, "invalid decode target: ring.TConfig", err)
+	})
+
+	t.Run("error - nil pointer", func(t *testing.T) {
+		// --- When ---
+		err := EnvDecode(NewEnv(nil), (*TConfig)(nil))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvTarget, err)
+	})
+
+	t.Run("error - not a struct", func(t *testing.T) {
+		// --- Given ---
+		var i int
+
+		// --- When ---
+		err := EnvDecode(NewEnv(nil), &i)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvTarget, err)
+	})
+}
+
+func Test_hasOption_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		opts string
+		want bool
+	}{
+		{"empty", "", false},
+		{"single", "required", true},
+		{"multiple", "other,required", true},
+		{"with spaces", "other, required", true},
+		{"not present", "other", false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have := hasOption(tc.opts, "required")
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
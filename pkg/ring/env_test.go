@@ -4,6 +4,7 @@
 package ring
 
 import (
+	"maps"
 	"os"
 	"slices"
 	"testing"
@@ -105,6 +106,185 @@ func Test_NewEnv(t *testing.T) {
 		assert.Len(t, 2, have.env)
 		assert.HasKeyValue(t, "A", "1", have.env)
 		assert.HasKeyValue(t, "B", "2", have.env)
+		assert.Nil(t, have.names)
+	})
+
+	t.Run("case-insensitive nil argument", func(t *testing.T) {
+		// --- When ---
+		have := NewEnv(nil, WithCaseInsensitive())
+
+		// --- Then ---
+		assert.NotNil(t, have.env)
+		assert.Len(t, 0, have.env)
+		assert.NotNil(t, have.names)
+	})
+
+	t.Run("case-insensitive", func(t *testing.T) {
+		// --- Given ---
+		env := []string{"Path=a", "B=2", "PATH=b", "path=c", "=x", "y"}
+
+		// --- When ---
+		have := NewEnv(env, WithCaseInsensitive())
+
+		// --- Then ---
+		assert.Equal(t, map[string]string{"Path": "c", "B": "2"}, have.env)
+		wNames := map[string]string{"PATH": "Path", "B": "B"}
+		assert.Equal(t, wNames, have.names)
+	})
+}
+
+func Test_WithCaseInsensitive(t *testing.T) {
+	// --- Given ---
+	env := &Env{}
+
+	// --- When ---
+	WithCaseInsensitive()(env)
+
+	// --- Then ---
+	assert.NotNil(t, env.names)
+	assert.Len(t, 0, env.names)
+}
+
+func Test_Env_EnvCaseInsensitive(t *testing.T) {
+	t.Run("case-sensitive", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have := env.EnvCaseInsensitive()
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+
+	t.Run("case-insensitive", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil, WithCaseInsensitive())
+
+		// --- When ---
+		have := env.EnvCaseInsensitive()
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+}
+
+func Test_Env_case_insensitive(t *testing.T) {
+	t.Run("lookup", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"Path=a"}, WithCaseInsensitive())
+
+		// --- When ---
+		have, exist := env.EnvLookup("PATH")
+
+		// --- Then ---
+		assert.True(t, exist)
+		assert.Equal(t, "a", have)
+	})
+
+	t.Run("set keeps the original spelling", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"Path=a"}, WithCaseInsensitive())
+
+		// --- When ---
+		env.EnvSet("PATH", "b")
+
+		// --- Then ---
+		assert.Equal(t, []string{"Path=b"}, env.EnvAll())
+		assert.Equal(t, "b", env.EnvGet("path"))
+	})
+
+	t.Run("set new variable", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil, WithCaseInsensitive())
+
+		// --- When ---
+		env.EnvSet("Path", "a")
+
+		// --- Then ---
+		assert.Equal(t, []string{"Path=a"}, env.EnvAll())
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"Path=a", "B=2"}, WithCaseInsensitive())
+
+		// --- When ---
+		env.EnvUnset("PATH")
+
+		// --- Then ---
+		assert.Equal(t, []string{"B=2"}, env.EnvAll())
+		assert.Equal(t, map[string]string{"B": "B"}, env.names)
+	})
+
+	t.Run("set after unset uses the new spelling", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"Path=a"}, WithCaseInsensitive())
+		env.EnvUnset("path")
+
+		// --- When ---
+		env.EnvSet("PATH", "b")
+
+		// --- Then ---
+		assert.Equal(t, []string{"PATH=b"}, env.EnvAll())
+	})
+
+	t.Run("case-sensitive by default", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"Path=a"})
+
+		// --- When ---
+		env.EnvSet("PATH", "b")
+
+		// --- Then ---
+		assert.Equal(t, []string{"PATH=b", "Path=a"}, env.EnvAll())
+		assert.Equal(t, "", env.EnvGet("path"))
+	})
+
+	t.Run("layer", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"Path=a", "Home=h"}, WithCaseInsensitive())
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		env.EnvSet("PATH", "b")
+		env.EnvUnset("HOME")
+
+		// --- Then ---
+		assert.Equal(t, []string{"Path=b"}, env.EnvAll())
+		assert.Equal(t, "b", env.EnvGet("path"))
+		assert.Equal(t, "", env.EnvGet("Home"))
+		assert.Equal(t, []string{"Home=h", "Path=a"}, parent.EnvAll())
+		set, unset := env.EnvLayer()
+		assert.Equal(t, map[string]string{"Path": "b"}, set)
+		assert.Equal(t, []string{"HOME"}, unset)
+	})
+
+	t.Run("layer set after unset", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"Path=a"}, WithCaseInsensitive())
+		env := NewEnvLayer(parent)
+		env.EnvUnset("PATH")
+
+		// --- When ---
+		env.EnvSet("path", "b")
+
+		// --- Then ---
+		assert.Equal(t, []string{"path=b"}, env.EnvAll())
+		assert.Equal(t, "b", env.EnvGet("Path"))
+	})
+
+	t.Run("layer falls through to parent", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"Path=a"}, WithCaseInsensitive())
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		have := env.EnvGet("PATH")
+
+		// --- Then ---
+		assert.Equal(t, "a", have)
+		assert.Equal(t, []string{"Path=a"}, env.EnvAll())
 	})
 }
 
@@ -161,6 +341,57 @@ func Test_Env_EnvSet(t *testing.T) {
 		// --- Then ---
 		assert.Equal(t, map[string]string{"A": "2", "B": "2"}, env.env)
 	})
+
+	t.Run("layer keeps parent spelling", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"Path=a"}, WithCaseInsensitive())
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		env.EnvSet("PATH", "c")
+
+		// --- Then ---
+		assert.Equal(t, []string{"Path=c"}, env.EnvAll())
+		assert.Equal(t, []string{"Path=a"}, parent.EnvAll())
+	})
+
+	t.Run("layer keeps grandparent spelling", func(t *testing.T) {
+		// --- Given ---
+		root := NewEnv([]string{"Path=a"}, WithCaseInsensitive())
+		parent := NewEnvLayer(NewEnvAudit(root))
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		env.EnvSet("PATH", "c")
+
+		// --- Then ---
+		assert.Equal(t, []string{"Path=c"}, env.EnvAll())
+	})
+
+	t.Run("layer after unset", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"Path=a"}, WithCaseInsensitive())
+		env := NewEnvLayer(parent)
+		env.EnvUnset("path")
+
+		// --- When ---
+		env.EnvSet("PATH", "c")
+
+		// --- Then ---
+		assert.Equal(t, []string{"PATH=c"}, env.EnvAll())
+	})
+
+	t.Run("layer new variable", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"Path=a"}, WithCaseInsensitive())
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		env.EnvSet("Home", "h")
+
+		// --- Then ---
+		assert.Equal(t, []string{"Home=h", "Path=a"}, env.EnvAll())
+	})
 }
 
 func Test_Env_EnvSetFrom(t *testing.T) {
@@ -211,6 +442,19 @@ func Test_Env_EnvSetWith(t *testing.T) {
 		// --- Then ---
 		assert.Equal(t, map[string]string{"A": "1", "B": "2"}, env.env)
 	})
+
+	t.Run("case-insensitive in order", func(t *testing.T) {
+		for range 50 {
+			// --- Given ---
+			env := NewEnv(nil, WithCaseInsensitive())
+
+			// --- When ---
+			env.EnvSetWith([]string{"Path=a", "PATH=b", "path=c"})
+
+			// --- Then ---
+			assert.Equal(t, []string{"Path=c"}, env.EnvAll())
+		}
+	})
 }
 
 func Test_Env_EnvUnset_tabular(t *testing.T) {
@@ -240,6 +484,29 @@ func Test_Env_EnvAll(t *testing.T) {
 		assert.Equal(t, []string{"A=1", "B=2"}, Sort(have))
 	})
 
+	t.Run("sorted by key", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"C=3", "A=1", "B=2", "AA=4"})
+
+		// --- When ---
+		have := env.EnvAll()
+
+		// --- Then ---
+		assert.Equal(t, []string{"A=1", "AA=4", "B=2", "C=3"}, have)
+	})
+
+	t.Run("layered sorted by key", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnvLayer(NewEnv([]string{"C=3", "A=1"}))
+		env.EnvSet("B", "2")
+
+		// --- When ---
+		have := env.EnvAll()
+
+		// --- Then ---
+		assert.Equal(t, []string{"A=1", "B=2", "C=3"}, have)
+	})
+
 	t.Run("empty environment", func(t *testing.T) {
 		// --- Given ---
 		env := &Env{}
@@ -253,18 +520,506 @@ func Test_Env_EnvAll(t *testing.T) {
 	})
 }
 
+func Test_Env_EnvKeys(t *testing.T) {
+	t.Run("sorted", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"C=3", "A=1", "B=2"})
+
+		// --- When ---
+		have := slices.Collect(env.EnvKeys())
+
+		// --- Then ---
+		assert.Equal(t, []string{"A", "B", "C"}, have)
+	})
+
+	t.Run("break", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"C=3", "A=1", "B=2"})
+
+		// --- When ---
+		var have []string
+		for key := range env.EnvKeys() {
+			have = append(have, key)
+			if key == "B" {
+				break
+			}
+		}
+
+		// --- Then ---
+		assert.Equal(t, []string{"A", "B"}, have)
+	})
+
+	t.Run("empty environment", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have := slices.Collect(env.EnvKeys())
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Env_EnvEach(t *testing.T) {
+	t.Run("sorted", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnvLayer(NewEnv([]string{"C=3", "A=1", "B=2"}))
+		env.EnvUnset("B")
+		env.EnvSet("D", "4")
+
+		// --- When ---
+		var have []string
+		for key, val := range env.EnvEach() {
+			have = append(have, key+"="+val)
+		}
+
+		// --- Then ---
+		assert.Equal(t, []string{"A=1", "C=3", "D=4"}, have)
+	})
+
+	t.Run("break", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"C=3", "A=1", "B=2"})
+
+		// --- When ---
+		var have []string
+		for key := range env.EnvEach() {
+			have = append(have, key)
+			break
+		}
+
+		// --- Then ---
+		assert.Equal(t, []string{"A"}, have)
+	})
+
+	t.Run("modify while iterating", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1", "B=2"})
+
+		// --- When ---
+		var have []string
+		for key, val := range env.EnvEach() {
+			have = append(have, key+"="+val)
+			env.EnvSet("B", "-2")
+			env.EnvSet("C", "3")
+		}
+
+		// --- Then ---
+		assert.Equal(t, []string{"A=1", "B=2"}, have)
+		assert.Equal(t, []string{"A=1", "B=-2", "C=3"}, env.EnvAll())
+	})
+}
+
+func Test_Env_EnvEachPrefix(t *testing.T) {
+	t.Run("matching", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"APP_B=2", "DB=3", "APP_A=1", "APPX=4"})
+
+		// --- When ---
+		have := maps.Collect(env.EnvEachPrefix("APP_"))
+
+		// --- Then ---
+		assert.Equal(t, map[string]string{"APP_A": "1", "APP_B": "2"}, have)
+	})
+
+	t.Run("order", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"APP_B=2", "DB=3", "APP_A=1"})
+
+		// --- When ---
+		var have []string
+		for key := range env.EnvEachPrefix("APP_") {
+			have = append(have, key)
+		}
+
+		// --- Then ---
+		assert.Equal(t, []string{"APP_A", "APP_B"}, have)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+
+		// --- When ---
+		have := maps.Collect(env.EnvEachPrefix("B"))
+
+		// --- Then ---
+		assert.Len(t, 0, have)
+	})
+}
+
 func Test_Env_EnvClone(t *testing.T) {
+	t.Run("not layered", func(t *testing.T) {
+		// --- Given ---
+		env := &Env{env: map[string]string{"A": "1"}}
+
+		// --- When ---
+		have := env.EnvClone()
+
+		// --- Then ---
+		assert.Equal(t, map[string]string{"A": "1"}, env.env)
+		assert.NotSame(t, env.env, have.env)
+		assert.NotSame(t, env, have)
+		assert.Nil(t, have.parent)
+		assert.Nil(t, have.hidden)
+		assert.Fields(t, 7, Env{})
+	})
+
+	t.Run("layered", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"A=1", "B=2"})
+		env := NewEnvLayer(parent)
+		env.EnvSet("C", "3")
+		env.EnvUnset("B")
+		env.EnvSensitive("A")
+
+		// --- When ---
+		have := env.EnvClone()
+
+		// --- Then ---
+		assert.Same(t, parent, have.parent)
+		assert.Equal(t, map[string]string{"C": "3"}, have.env)
+		assert.NotSame(t, env.env, have.env)
+		assert.Equal(t, map[string]struct{}{"B": {}}, have.hidden)
+		assert.NotSame(t, env.hidden, have.hidden)
+		assert.Equal(t, []string{"A"}, have.secrets)
+		assert.NotSame(t, env.secrets, have.secrets)
+	})
+
+	t.Run("frozen", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+		env.EnvFreeze()
+
+		// --- When ---
+		have := env.EnvClone()
+
+		// --- Then ---
+		assert.False(t, have.frozen)
+		have.EnvSet("B", "2")
+		assert.Equal(t, []string{"A=1", "B=2"}, have.EnvAll())
+		assert.Equal(t, []string{"A=1"}, env.EnvAll())
+	})
+
+	t.Run("frozen layered", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"A=1"})
+		env := NewEnvLayer(parent)
+		env.EnvFreeze()
+
+		// --- When ---
+		have := env.EnvClone()
+
+		// --- Then ---
+		assert.False(t, have.frozen)
+		ro, _ := have.parent.(*EnvReadOnly)
+		assert.NotNil(t, ro)
+		have.EnvParent().EnvSet("A", "x")
+		assert.ErrorIs(t, ErrReadOnly, ro.Err())
+		assert.Equal(t, []string{"A=1"}, parent.EnvAll())
+		have.EnvSet("A", "2")
+		assert.Equal(t, []string{"A=2"}, have.EnvAll())
+	})
+
+	t.Run("case-insensitive", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"Path=a"}, WithCaseInsensitive())
+
+		// --- When ---
+		have := env.EnvClone()
+
+		// --- Then ---
+		assert.Equal(t, map[string]string{"PATH": "Path"}, have.names)
+		assert.NotSame(t, env.names, have.names)
+		have.EnvSet("PATH", "b")
+		assert.Equal(t, []string{"Path=b"}, have.EnvAll())
+	})
+}
+
+func Test_NewEnvLayer(t *testing.T) {
+	// --- Given ---
+	parent := NewEnv([]string{"A=1"})
+
+	// --- When ---
+	have := NewEnvLayer(parent)
+
+	// --- Then ---
+	assert.Same(t, parent, have.parent)
+	assert.NotNil(t, have.env)
+	assert.Len(t, 0, have.env)
+	assert.NotNil(t, have.hidden)
+	assert.Len(t, 0, have.hidden)
+	assert.Nil(t, have.names)
+}
+
+func Test_NewEnvLayer_case_insensitive(t *testing.T) {
+	t.Run("case-insensitive parent", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv(nil, WithCaseInsensitive())
+
+		// --- When ---
+		have := NewEnvLayer(parent)
+
+		// --- Then ---
+		assert.True(t, have.EnvCaseInsensitive())
+	})
+
+	t.Run("audited parent", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnvAudit(NewEnv(nil, WithCaseInsensitive()))
+
+		// --- When ---
+		have := NewEnvLayer(parent)
+
+		// --- Then ---
+		assert.True(t, have.EnvCaseInsensitive())
+	})
+
+	t.Run("read-only parent", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil, WithCaseInsensitive())
+		parent := NewEnvReadOnly(NewEnvAudit(env))
+
+		// --- When ---
+		have := NewEnvLayer(parent)
+
+		// --- Then ---
+		assert.True(t, have.EnvCaseInsensitive())
+	})
+
+	t.Run("not Env parent", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnvScope(NewEnv(nil, WithCaseInsensitive()), "A_")
+
+		// --- When ---
+		have := NewEnvLayer(parent)
+
+		// --- Then ---
+		assert.False(t, have.EnvCaseInsensitive())
+	})
+}
+
+func Test_Env_layered(t *testing.T) {
+	t.Run("lookup falls through to parent", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"A=1"})
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		have, exist := env.EnvLookup("A")
+
+		// --- Then ---
+		assert.True(t, exist)
+		assert.Equal(t, "1", have)
+		assert.Equal(t, "1", env.EnvGet("A"))
+	})
+
+	t.Run("set is recorded only in the layer", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"A=1"})
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		env.EnvSet("A", "2")
+		env.EnvSet("B", "3")
+
+		// --- Then ---
+		assert.Equal(t, "2", env.EnvGet("A"))
+		assert.Equal(t, "3", env.EnvGet("B"))
+		assert.Equal(t, []string{"A=1"}, parent.EnvAll())
+	})
+
+	t.Run("unset hides parent value", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"A=1"})
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		env.EnvUnset("A")
+
+		// --- Then ---
+		have, exist := env.EnvLookup("A")
+		assert.False(t, exist)
+		assert.Equal(t, "", have)
+		assert.Nil(t, env.EnvAll())
+		assert.Equal(t, []string{"A=1"}, parent.EnvAll())
+	})
+
+	t.Run("set after unset", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"A=1"})
+		env := NewEnvLayer(parent)
+		env.EnvUnset("A")
+
+		// --- When ---
+		env.EnvSet("A", "2")
+
+		// --- Then ---
+		assert.Equal(t, "2", env.EnvGet("A"))
+		assert.Len(t, 0, env.hidden)
+	})
+
+	t.Run("parent changes are visible", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"A=1"})
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		parent.EnvSet("B", "2")
+
+		// --- Then ---
+		assert.Equal(t, "2", env.EnvGet("B"))
+	})
+
+	t.Run("all merges layers", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"A=1", "B=2", "C=3"})
+		env := NewEnvLayer(parent)
+		env.EnvSet("B", "-2")
+		env.EnvUnset("C")
+		env.EnvSet("D", "4")
+
+		// --- When ---
+		have := env.EnvAll()
+
+		// --- Then ---
+		assert.Equal(t, []string{"A=1", "B=-2", "D=4"}, Sort(have))
+	})
+
+	t.Run("multiple layers", func(t *testing.T) {
+		// --- Given ---
+		root := NewEnv([]string{"A=1", "B=2"})
+		mid := NewEnvLayer(root)
+		mid.EnvUnset("A")
+		env := NewEnvLayer(mid)
+
+		// --- When ---
+		env.EnvSet("C", "3")
+
+		// --- Then ---
+		assert.Equal(t, []string{"B=2", "C=3"}, Sort(env.EnvAll()))
+	})
+}
+
+func Test_Env_EnvParent(t *testing.T) {
+	t.Run("not layered", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have := env.EnvParent()
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+
+	t.Run("layered", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv(nil)
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		have := env.EnvParent()
+
+		// --- Then ---
+		assert.Same(t, parent, have)
+	})
+
+	t.Run("frozen", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"A=1"})
+		env := NewEnvLayer(parent)
+		env.EnvFreeze()
+
+		// --- When ---
+		have := env.EnvParent()
+
+		// --- Then ---
+		ro, _ := have.(*EnvReadOnly)
+		assert.NotNil(t, ro)
+		have.EnvSet("A", "2")
+		assert.ErrorIs(t, ErrReadOnly, ro.Err())
+		assert.Equal(t, []string{"A=1"}, parent.EnvAll())
+	})
+
+	t.Run("frozen not layered", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		env.EnvFreeze()
+
+		// --- When ---
+		have := env.EnvParent()
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Env_EnvFlatten(t *testing.T) {
 	// --- Given ---
-	env := &Env{env: map[string]string{"A": "1"}}
+	parent := NewEnv([]string{"A=1", "B=2"})
+	parent.EnvSensitive("B")
+	env := NewEnvLayer(parent)
+	env.EnvSet("C", "3")
+	env.EnvUnset("A")
+	env.EnvSensitive("C")
 
 	// --- When ---
-	have := env.EnvClone()
+	have := env.EnvFlatten()
 
 	// --- Then ---
-	assert.Equal(t, map[string]string{"A": "1"}, env.env)
-	assert.NotSame(t, env.env, have.env)
-	assert.NotSame(t, env, have)
-	assert.Fields(t, 1, Env{})
+	assert.Nil(t, have.parent)
+	assert.Equal(t, []string{"C", "B"}, have.secrets)
+	assert.Equal(t, map[string]string{"B": "2", "C": "3"}, have.env)
+	parent.EnvSet("D", "4")
+	assert.Equal(t, []string{"B=2", "C=3"}, Sort(have.EnvAll()))
+	assert.False(t, have.EnvCaseInsensitive())
+}
+
+func Test_Env_EnvFlatten_case_insensitive(t *testing.T) {
+	// --- Given ---
+	env := NewEnvLayer(NewEnv([]string{"Path=a"}, WithCaseInsensitive()))
+
+	// --- When ---
+	have := env.EnvFlatten()
+
+	// --- Then ---
+	assert.True(t, have.EnvCaseInsensitive())
+	assert.Equal(t, "a", have.EnvGet("PATH"))
+}
+
+func Test_Env_EnvLayer(t *testing.T) {
+	t.Run("not layered", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+		env.EnvUnset("A")
+		env.EnvSet("B", "2")
+
+		// --- When ---
+		set, unset := env.EnvLayer()
+
+		// --- Then ---
+		assert.Equal(t, map[string]string{"B": "2"}, set)
+		assert.Nil(t, unset)
+	})
+
+	t.Run("layered", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"A=1", "B=2", "C=3"})
+		env := NewEnvLayer(parent)
+		env.EnvSet("A", "-1")
+		env.EnvUnset("C")
+		env.EnvUnset("B")
+
+		// --- When ---
+		set, unset := env.EnvLayer()
+
+		// --- Then ---
+		assert.Equal(t, map[string]string{"A": "-1"}, set)
+		assert.NotSame(t, env.env, set)
+		assert.Equal(t, []string{"B", "C"}, unset)
+	})
 }
 
 func Test_EnvSet(t *testing.T) {
@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvGetDefault retrieves the value of the environment variable named by the
+// key. If the key is not present in the environment, it returns def value.
+func (env *Env) EnvGetDefault(key, def string) string {
+	if val, exist := env.EnvLookup(key); exist {
+		return val
+	}
+	return def
+}
+
+// EnvMustGet retrieves the value of the environment variable named by the
+// key. It returns an error wrapping [ErrReqEnv] when the key is not set.
+func (env *Env) EnvMustGet(key string) (string, error) {
+	return envMustParse(env, key, parseString)
+}
+
+// EnvInt parses the environment variable named by the key as an int. It
+// returns def when the key is not set. When the value cannot be parsed, it
+// returns def and an error wrapping [ErrInvEnv].
+func (env *Env) EnvInt(key string, def int) (int, error) {
+	return envParse(env, key, def, strconv.Atoi)
+}
+
+// EnvMustInt parses the environment variable named by the key as an int. It
+// returns an error wrapping [ErrReqEnv] when the key is not set or an error
+// wrapping [ErrInvEnv] when the value cannot be parsed.
+func (env *Env) EnvMustInt(key string) (int, error) {
+	return envMustParse(env, key, strconv.Atoi)
+}
+
+// EnvFloat parses the environment variable named by the key as a float64. It
+// returns def when the key is not set. When the value cannot be parsed, it
+// returns def and an error wrapping [ErrInvEnv].
+func (env *Env) EnvFloat(key string, def float64) (float64, error) {
+	return envParse(env, key, def, parseFloat)
+}
+
+// EnvMustFloat parses the environment variable named by the key as a
+// float64. It returns an error wrapping [ErrReqEnv] when the key is not set
+// or an error wrapping [ErrInvEnv] when the value cannot be parsed.
+func (env *Env) EnvMustFloat(key string) (float64, error) {
+	return envMustParse(env, key, parseFloat)
+}
+
+// EnvBool parses the environment variable named by the key as a bool using
+// [strconv.ParseBool] rules. It returns def when the key is not set. When the
+// value cannot be parsed, it returns def and an error wrapping [ErrInvEnv].
+func (env *Env) EnvBool(key string, def bool) (bool, error) {
+	return envParse(env, key, def, strconv.ParseBool)
+}
+
+// EnvMustBool parses the environment variable named by the key as a bool
+// using [strconv.ParseBool] rules. It returns an error wrapping [ErrReqEnv]
+// when the key is not set or an error wrapping [ErrInvEnv] when the value
+// cannot be parsed.
+func (env *Env) EnvMustBool(key string) (bool, error) {
+	return envMustParse(env, key, strconv.ParseBool)
+}
+
+// EnvDuration parses the environment variable named by the key using
+// [time.ParseDuration]. It returns def when the key is not set. When the
+// value cannot be parsed, it returns def and an error wrapping [ErrInvEnv].
+func (env *Env) EnvDuration(key string, def time.Duration) (
+	time.Duration,
+	error,
+) {
+	return envParse(env, key, def, time.ParseDuration)
+}
+
+// EnvMustDuration parses the environment variable named by the key using
+// [time.ParseDuration]. It returns an error wrapping [ErrReqEnv] when the key
+// is not set or an error wrapping [ErrInvEnv] when the value cannot be
+// parsed.
+func (env *Env) EnvMustDuration(key string) (time.Duration, error) {
+	return envMustParse(env, key, time.ParseDuration)
+}
+
+// EnvURL parses the environment variable named by the key using [url.Parse].
+// It returns def when the key is not set. When the value cannot be parsed, it
+// returns def and an error wrapping [ErrInvEnv].
+func (env *Env) EnvURL(key string, def *url.URL) (*url.URL, error) {
+	return envParse(env, key, def, url.Parse)
+}
+
+// EnvMustURL parses the environment variable named by the key using
+// [url.Parse]. It returns an error wrapping [ErrReqEnv] when the key is not
+// set or an error wrapping [ErrInvEnv] when the value cannot be parsed.
+func (env *Env) EnvMustURL(key string) (*url.URL, error) {
+	return envMustParse(env, key, url.Parse)
+}
+
+// EnvList splits the environment variable named by the key on commas. Items
+// are trimmed of surrounding white space and empty items are skipped. It
+// returns def when the key is not set.
+func (env *Env) EnvList(key string, def []string) []string {
+	ret, _ := envParse(env, key, def, parseList)
+	return ret
+}
+
+// EnvMustList splits the environment variable named by the key on commas the
+// same way [Env.EnvList] does. It returns an error wrapping [ErrReqEnv] when
+// the key is not set.
+func (env *Env) EnvMustList(key string) ([]string, error) {
+	return envMustParse(env, key, parseList)
+}
+
+// envParse looks up the key in the environment and parses its value with the
+// parse function. Returns def when the key is not set or its value is
+// invalid, in the latter case with an error wrapping [ErrInvEnv].
+func envParse[T any](
+	env Environ,
+	key string,
+	def T,
+	parse func(string) (T, error),
+) (T, error) {
+	val, exist := env.EnvLookup(key)
+	if !exist {
+		return def, nil
+	}
+	ret, err := parse(val)
+	if err != nil {
+		return def, envInvalid(key, val, err)
+	}
+	return ret, nil
+}
+
+// envMustParse looks up the key in the environment and parses its value with
+// the parse function. Returns an error wrapping [ErrReqEnv] when the key is
+// not set or [ErrInvEnv] when the value is invalid.
+func envMustParse[T any](
+	env Environ,
+	key string,
+	parse func(string) (T, error),
+) (T, error) {
+	val, exist := env.EnvLookup(key)
+	if !exist {
+		var zero T
+		return zero, envRequired(key)
+	}
+	ret, err := parse(val)
+	if err != nil {
+		var zero T
+		return zero, envInvalid(key, val, err)
+	}
+	return ret, nil
+}
+
+// envRequired returns an error wrapping [ErrReqEnv] for the given key.
+func envRequired(key string) error {
+	return fmt.Errorf("%w: %s", ErrReqEnv, key)
+}
+
+// envInvalid returns an error wrapping [ErrInvEnv] naming the key and the
+// offending value. The cause is unwrapped from [strconv.NumError] and
+// [url.Error] to avoid repeating the value in the message.
+func envInvalid(key, val string, cause error) error {
+	var numErr *strconv.NumError
+	if errors.As(cause, &numErr) {
+		cause = numErr.Err
+	}
+	var urlErr *url.Error
+	if errors.As(cause, &urlErr) {
+		cause = urlErr.Err
+	}
+	return fmt.Errorf("%w: %s=%q: %w", ErrInvEnv, key, val, cause)
+}
+
+// parseString is a no-op parser returning its input.
+func parseString(val string) (string, error) { return val, nil }
+
+// parseFloat parses a 64-bit float.
+func parseFloat(val string) (float64, error) {
+	return strconv.ParseFloat(val, 64)
+}
+
+// parseList splits a comma separated list, trims items and skips empty ones.
+func parseList(val string) ([]string, error) {
+	var ret []string
+	for item := range strings.SplitSeq(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			ret = append(ret, item)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,465 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+func Test_Env_EnvGetDefault_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		env  []string
+		key  string
+		def  string
+		want string
+	}{
+		{"get existing value", []string{"A=1", "B=2"}, "A", "x", "1"},
+		{"get empty value", []string{"A=", "B=2"}, "A", "x", ""},
+		{"get default value", []string{"A=1", "B=2"}, "C", "x", "x"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- Given ---
+			env := NewEnv(tc.env)
+
+			// --- When ---
+			have := env.EnvGetDefault(tc.key, tc.def)
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_Env_EnvMustGet(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A="})
+
+		// --- When ---
+		have, err := env.EnvMustGet("A")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "", have)
+	})
+
+	t.Run("error - not set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have, err := env.EnvMustGet("A")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReqEnv, err)
+		assert.ErrorEqual(t, "required environment variable: A", err)
+		assert.Equal(t, "", have)
+	})
+}
+
+func Test_Env_EnvInt(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=-42"})
+
+		// --- When ---
+		have, err := env.EnvInt("A", 1)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, -42, have)
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have, err := env.EnvInt("A", 1)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 1, have)
+	})
+
+	t.Run("error - invalid", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=abc"})
+
+		// --- When ---
+		have, err := env.EnvInt("A", 1)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "invalid environment variable: A=\"abc\": invalid syntax"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Equal(t, 1, have)
+	})
+}
+
+func Test_Env_EnvMustInt(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=42"})
+
+		// --- When ---
+		have, err := env.EnvMustInt("A")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 42, have)
+	})
+
+	t.Run("error - not set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have, err := env.EnvMustInt("A")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReqEnv, err)
+		assert.Equal(t, 0, have)
+	})
+
+	t.Run("error - empty value", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A="})
+
+		// --- When ---
+		have, err := env.EnvMustInt("A")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		assert.Equal(t, 0, have)
+	})
+}
+
+func Test_Env_EnvFloat(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1.5"})
+
+		// --- When ---
+		have, err := env.EnvFloat("A", 2)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 1.5, have)
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have, err := env.EnvFloat("A", 2)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 2.0, have)
+	})
+
+	t.Run("error - invalid", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=abc"})
+
+		// --- When ---
+		have, err := env.EnvFloat("A", 2)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		assert.Equal(t, 2.0, have)
+	})
+}
+
+func Test_Env_EnvMustFloat(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1.5"})
+
+		// --- When ---
+		have, err := env.EnvMustFloat("A")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 1.5, have)
+	})
+
+	t.Run("error - not set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have, err := env.EnvMustFloat("A")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReqEnv, err)
+		assert.Equal(t, 0.0, have)
+	})
+}
+
+func Test_Env_EnvBool(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=true"})
+
+		// --- When ---
+		have, err := env.EnvBool("A", false)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.True(t, have)
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have, err := env.EnvBool("A", true)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.True(t, have)
+	})
+
+	t.Run("error - invalid", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=yes"})
+
+		// --- When ---
+		have, err := env.EnvBool("A", true)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "invalid environment variable: A=\"yes\": invalid syntax"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.True(t, have)
+	})
+}
+
+func Test_Env_EnvMustBool(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=0"})
+
+		// --- When ---
+		have, err := env.EnvMustBool("A")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.False(t, have)
+	})
+
+	t.Run("error - not set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have, err := env.EnvMustBool("A")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReqEnv, err)
+		assert.False(t, have)
+	})
+}
+
+func Test_Env_EnvDuration(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1m30s"})
+
+		// --- When ---
+		have, err := env.EnvDuration("A", time.Second)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 90*time.Second, have)
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have, err := env.EnvDuration("A", time.Second)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, time.Second, have)
+	})
+
+	t.Run("error - invalid", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=abc"})
+
+		// --- When ---
+		have, err := env.EnvDuration("A", time.Second)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "invalid environment variable: A=\"abc\": " +
+			"time: invalid duration \"abc\""
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Equal(t, time.Second, have)
+	})
+}
+
+func Test_Env_EnvMustDuration(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=2s"})
+
+		// --- When ---
+		have, err := env.EnvMustDuration("A")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 2*time.Second, have)
+	})
+
+	t.Run("error - not set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have, err := env.EnvMustDuration("A")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReqEnv, err)
+		assert.Equal(t, time.Duration(0), have)
+	})
+}
+
+func Test_Env_EnvURL(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=https://example.com/path"})
+
+		// --- When ---
+		have, err := env.EnvURL("A", nil)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "https://example.com/path", have.String())
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		def := &url.URL{Scheme: "http", Host: "localhost"}
+
+		// --- When ---
+		have, err := env.EnvURL("A", def)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Same(t, def, have)
+	})
+
+	t.Run("error - invalid", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=:bad"})
+
+		// --- When ---
+		have, err := env.EnvURL("A", nil)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "invalid environment variable: A=\":bad\": " +
+			"missing protocol scheme"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Env_EnvMustURL(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=http://localhost:8080"})
+
+		// --- When ---
+		have, err := env.EnvMustURL("A")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "localhost:8080", have.Host)
+	})
+
+	t.Run("error - not set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have, err := env.EnvMustURL("A")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReqEnv, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Env_EnvList_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		env  []string
+		want []string
+	}{
+		{"not set", nil, []string{"def"}},
+		{"empty", []string{"A="}, nil},
+		{"single", []string{"A=a"}, []string{"a"}},
+		{"multiple", []string{"A=a,b,c"}, []string{"a", "b", "c"}},
+		{"trimmed", []string{"A= a , b "}, []string{"a", "b"}},
+		{"skip empty items", []string{"A=a,,b,"}, []string{"a", "b"}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- Given ---
+			env := NewEnv(tc.env)
+
+			// --- When ---
+			have := env.EnvList("A", []string{"def"})
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_Env_EnvMustList(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=a,b"})
+
+		// --- When ---
+		have, err := env.EnvMustList("A")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, have)
+	})
+
+	t.Run("error - not set", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have, err := env.EnvMustList("A")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReqEnv, err)
+		assert.Nil(t, have)
+	})
+}
@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// EnvdirTrim represents how values read from envdir files are trimmed.
+type EnvdirTrim int
+
+// Envdir value trimming rules.
+const (
+	// TrimNewline removes a single trailing "\n" or "\r\n" from the value.
+	TrimNewline EnvdirTrim = iota
+
+	// TrimNone uses the file content as is, the same way Kubernetes secret
+	// volumes are meant to be read.
+	TrimNone
+
+	// TrimSpace removes leading and trailing white space from the value.
+	TrimSpace
+
+	// TrimDaemontools follows the daemontools envdir rules: the value is the
+	// first line of the file with trailing spaces and tabs removed and NUL
+	// bytes replaced with newlines. An empty file unsets the variable.
+	TrimDaemontools
+)
+
+// EnvdirOption configures [Env.EnvLoadEnvdir].
+type EnvdirOption func(*envdirConfig)
+
+// envdirConfig represents [Env.EnvLoadEnvdir] configuration.
+type envdirConfig struct {
+	trim      EnvdirTrim // Value trimming rule.
+	maxSize   int64      // Maximum file size in bytes, zero for no limit.
+	hidden    bool       // Load files with names starting with a dot.
+	sensitive bool       // Mark loaded variables as sensitive.
+}
+
+// WithEnvdirTrim configures the trimming rule for values. By default,
+// [TrimNewline] is used.
+func WithEnvdirTrim(trim EnvdirTrim) EnvdirOption {
+	return func(cfg *envdirConfig) { cfg.trim = trim }
+}
+
+// WithEnvdirMaxSize configures the maximum size of a file in bytes. Loading
+// fails when any of the files is bigger. By default, there is no limit.
+func WithEnvdirMaxSize(size int64) EnvdirOption {
+	return func(cfg *envdirConfig) { cfg.maxSize = size }
+}
+
+// WithEnvdirHidden configures loading of files with names starting with a
+// dot, which are skipped by default.
+func WithEnvdirHidden() EnvdirOption {
+	return func(cfg *envdirConfig) { cfg.hidden = true }
+}
+
+// WithEnvdirSensitive configures marking all variables found in the
+// directory as sensitive, see [Env.EnvSensitive].
+func WithEnvdirSensitive() EnvdirOption {
+	return func(cfg *envdirConfig) { cfg.sensitive = true }
+}
+
+// EnvLoadEnvdir loads variables from the directory in the filesystem where
+// each regular file defines one variable: the file name is the variable name
+// and the file content is its value, as used by daemontools envdir and
+// Kubernetes secret volumes. Subdirectories are skipped, symbolic links are
+// followed. The precedence decides if loaded values override existing
+// variables.
+//
+// The environment is not modified when any of the files cannot be read, is
+// bigger than the configured limit or has a name containing "=", in which
+// case an error wrapping [ErrInvEnv] is returned. Returns [ErrReadOnly] for a
+// frozen environment.
+func (env *Env) EnvLoadEnvdir(
+	fsys fs.FS,
+	dir string,
+	prec Precedence,
+	opts ...EnvdirOption,
+) error {
+	if env.EnvFrozen() {
+		return ErrReadOnly
+	}
+	cfg := &envdirConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	var keys, unset []string
+	set := make(map[string]string)
+	for _, entry := range entries {
+		key := entry.Name()
+		if !cfg.hidden && strings.HasPrefix(key, ".") {
+			continue
+		}
+		name := path.Join(dir, key)
+		info, err := fs.Stat(fsys, name)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			continue
+		}
+		if strings.Contains(key, "=") {
+			return fmt.Errorf("%w: %s: invalid name", ErrInvEnv, name)
+		}
+		data, err := readFileMax(fsys, name, cfg.maxSize)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		if _, exist := env.EnvLookup(key); exist && prec == FillGaps {
+			continue
+		}
+		val, ok := cfg.trim.apply(data)
+		if !ok {
+			unset = append(unset, key)
+			continue
+		}
+		set[key] = val
+	}
+
+	if cfg.sensitive && len(keys) > 0 {
+		env.EnvSensitive(keys...)
+	}
+	for _, key := range unset {
+		env.EnvUnset(key)
+	}
+	env.EnvSetFrom(set)
+	return nil
+}
+
+// apply returns the trimmed value. It returns false when the variable should
+// be unset.
+func (trim EnvdirTrim) apply(data []byte) (string, bool) {
+	switch trim {
+	case TrimNone:
+		return string(data), true
+	case TrimSpace:
+		return string(bytes.TrimSpace(data)), true
+	case TrimDaemontools:
+		if len(data) == 0 {
+			return "", false
+		}
+		data, _, _ = bytes.Cut(data, []byte{'\n'})
+		data = bytes.TrimRight(data, " \t")
+		return string(bytes.ReplaceAll(data, []byte{0}, []byte{'\n'})), true
+	default:
+		if trimmed, ok := bytes.CutSuffix(data, []byte{'\n'}); ok {
+			data = bytes.TrimSuffix(trimmed, []byte{'\r'})
+		}
+		return string(data), true
+	}
+}
+
+// readFileMax reads the named file from the filesystem. When maxSize is
+// greater than zero and the file is bigger, it returns an error wrapping
+// [ErrInvEnv].
+func readFileMax(fsys fs.FS, name string, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		return fs.ReadFile(fsys, name)
+	}
+	fil, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = fil.Close() }()
+	data, err := io.ReadAll(io.LimitReader(fil, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf(
+			"%w: %s: bigger than %d bytes",
+			ErrInvEnv,
+			name,
+			maxSize,
+		)
+	}
+	return data, nil
+}
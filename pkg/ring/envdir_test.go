@@ -0,0 +1,371 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+func Test_WithEnvdirTrim(t *testing.T) {
+	// --- Given ---
+	cfg := &envdirConfig{}
+
+	// --- When ---
+	WithEnvdirTrim(TrimSpace)(cfg)
+
+	// --- Then ---
+	assert.Equal(t, TrimSpace, cfg.trim)
+}
+
+func Test_WithEnvdirMaxSize(t *testing.T) {
+	// --- Given ---
+	cfg := &envdirConfig{}
+
+	// --- When ---
+	WithEnvdirMaxSize(10)(cfg)
+
+	// --- Then ---
+	assert.Equal(t, int64(10), cfg.maxSize)
+}
+
+func Test_WithEnvdirHidden(t *testing.T) {
+	// --- Given ---
+	cfg := &envdirConfig{}
+
+	// --- When ---
+	WithEnvdirHidden()(cfg)
+
+	// --- Then ---
+	assert.True(t, cfg.hidden)
+}
+
+func Test_WithEnvdirSensitive(t *testing.T) {
+	// --- Given ---
+	cfg := &envdirConfig{}
+
+	// --- When ---
+	WithEnvdirSensitive()(cfg)
+
+	// --- Then ---
+	assert.True(t, cfg.sensitive)
+}
+
+func Test_Env_EnvLoadEnvdir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"env/A":       {Data: []byte("file\n")},
+		"env/B":       {Data: []byte("file")},
+		"env/.hidden": {Data: []byte("hidden\n")},
+		"env/sub/C":   {Data: []byte("sub\n")},
+		"empty/E":     {Data: []byte("")},
+		"big/A":       {Data: []byte("12345")},
+		"bad/A=B":     {Data: []byte("1")},
+	}
+
+	t.Run("fill gaps", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=env"})
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "env", FillGaps)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"A=env", "B=file"}, env.EnvAll())
+	})
+
+	t.Run("override", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=env"})
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "env", Override)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"A=file", "B=file"}, env.EnvAll())
+	})
+
+	t.Run("hidden", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "env", Override, WithEnvdirHidden())
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := []string{".hidden=hidden", "A=file", "B=file"}
+		assert.Equal(t, want, env.EnvAll())
+	})
+
+	t.Run("trim none", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		opt := WithEnvdirTrim(TrimNone)
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "env", Override, opt)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"A=file\n", "B=file"}, env.EnvAll())
+	})
+
+	t.Run("sensitive", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=env", "C=env"})
+		opt := WithEnvdirSensitive()
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "env", FillGaps, opt)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.True(t, env.EnvIsSensitive("A"))
+		assert.True(t, env.EnvIsSensitive("B"))
+		assert.False(t, env.EnvIsSensitive("C"))
+		want := []string{"A=[REDACTED]", "B=[REDACTED]", "C=env"}
+		assert.Equal(t, want, env.EnvRedacted())
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "empty", Override)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"E="}, env.EnvAll())
+	})
+
+	t.Run("daemontools empty file unsets", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"E=env"})
+		opt := WithEnvdirTrim(TrimDaemontools)
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "empty", Override, opt)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, env.EnvAll())
+	})
+
+	t.Run("daemontools empty file fill gaps", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"E=env"})
+		opt := WithEnvdirTrim(TrimDaemontools)
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "empty", FillGaps, opt)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"E=env"}, env.EnvAll())
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "big", Override, WithEnvdirMaxSize(5))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"A=12345"}, env.EnvAll())
+	})
+
+	t.Run("layered", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"A=parent"})
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "env", Override)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"A=file", "B=file"}, env.EnvAll())
+		assert.Equal(t, []string{"A=parent"}, parent.EnvAll())
+	})
+
+	t.Run("error - max size", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=env"})
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "big", Override, WithEnvdirMaxSize(4))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "invalid environment variable: big/A: bigger than 4 bytes"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Equal(t, []string{"A=env"}, env.EnvAll())
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "bad", Override)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "invalid environment variable: bad/A=B: invalid name"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, env.EnvAll())
+	})
+
+	t.Run("error - directory does not exist", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "missing", Override)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+	})
+
+	t.Run("error - frozen", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		env.EnvFreeze()
+
+		// --- When ---
+		err := env.EnvLoadEnvdir(fsys, "env", Override)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReadOnly, err)
+	})
+}
+
+func Test_Env_EnvLoadEnvdir_kubernetes_volume(t *testing.T) {
+	t.Run("symlinks", func(t *testing.T) {
+		// --- Given ---
+		dir := t.TempDir()
+		data := filepath.Join(dir, "..2025_01_01_00_00_00.1")
+		assert.NoError(t, os.Mkdir(data, 0o755))
+		err := os.WriteFile(filepath.Join(data, "TOKEN"), []byte("abc"), 0o600)
+		assert.NoError(t, err)
+		link := filepath.Join(dir, "..data")
+		assert.NoError(t, os.Symlink(filepath.Base(data), link))
+		tok := filepath.Join("..data", "TOKEN")
+		assert.NoError(t, os.Symlink(tok, filepath.Join(dir, "TOKEN")))
+		env := NewEnv(nil)
+
+		// --- When ---
+		err = env.EnvLoadEnvdir(
+			os.DirFS(dir),
+			".",
+			Override,
+			WithEnvdirTrim(TrimNone),
+			WithEnvdirSensitive(),
+		)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"TOKEN=abc"}, env.EnvAll())
+		assert.True(t, env.EnvIsSensitive("TOKEN"))
+	})
+
+	t.Run("error - broken symlink", func(t *testing.T) {
+		// --- Given ---
+		dir := t.TempDir()
+		err := os.Symlink("missing", filepath.Join(dir, "A"))
+		assert.NoError(t, err)
+		env := NewEnv(nil)
+
+		// --- When ---
+		err = env.EnvLoadEnvdir(os.DirFS(dir), ".", Override)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+	})
+}
+
+func Test_EnvdirTrim_apply_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		trim  EnvdirTrim
+		data  string
+		want  string
+		wKeep bool
+	}{
+		{"newline", TrimNewline, "a b \n", "a b ", true},
+		{"newline crlf", TrimNewline, "a\r\n", "a", true},
+		{"newline only one", TrimNewline, "a\n\n", "a\n", true},
+		{"newline no newline", TrimNewline, "a\r", "a\r", true},
+		{"newline empty", TrimNewline, "", "", true},
+		{"none", TrimNone, " a\n", " a\n", true},
+		{"space", TrimSpace, " \ta\n\n", "a", true},
+		{"daemontools", TrimDaemontools, "a \t\nb\n", "a", true},
+		{"daemontools nul", TrimDaemontools, "a\x00b\x00", "a\nb\n", true},
+		{"daemontools empty line", TrimDaemontools, "\n", "", true},
+		{"daemontools empty file", TrimDaemontools, "", "", false},
+		{"unknown", EnvdirTrim(99), "a\n", "a", true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have, keep := tc.trim.apply([]byte(tc.data))
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+			assert.Equal(t, tc.wKeep, keep)
+		})
+	}
+}
+
+func Test_readFileMax(t *testing.T) {
+	fsys := fstest.MapFS{"A": {Data: []byte("123")}}
+
+	t.Run("no limit", func(t *testing.T) {
+		// --- When ---
+		have, err := readFileMax(fsys, "A", 0)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "123", string(have))
+	})
+
+	t.Run("within limit", func(t *testing.T) {
+		// --- When ---
+		have, err := readFileMax(fsys, "A", 3)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "123", string(have))
+	})
+
+	t.Run("error - over limit", func(t *testing.T) {
+		// --- When ---
+		have, err := readFileMax(fsys, "A", 2)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - does not exist", func(t *testing.T) {
+		// --- When ---
+		have, err := readFileMax(fsys, "B", 2)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+		assert.Nil(t, have)
+	})
+}
@@ -0,0 +1,198 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expand replaces shell-style variable references in s with values from the
+// environment. Undefined variables are replaced with empty strings.
+//
+// Supported forms:
+//   - $VAR, ${VAR} - the value of VAR,
+//   - ${VAR:-word} - word if VAR is not set or empty, ${VAR-word} - word
+//     if VAR is not set,
+//   - ${VAR:+word} - word if VAR is set and not empty, ${VAR+word} - word
+//     if VAR is set,
+//   - ${VAR:?msg} - error if VAR is not set or empty, ${VAR?msg} - error if
+//     VAR is not set,
+//   - $$ - literal "$".
+//
+// The word and msg are expanded recursively. The "$" not followed by a name
+// or "{" is kept as is. The "${VAR:?msg}" errors wrap [ErrReqEnv], malformed
+// references return errors wrapping [ErrBadSubst].
+func Expand(env Environ, s string) (string, error) {
+	return expander{lookup: env.EnvLookup}.expand(s)
+}
+
+// ExpandStrict works like [Expand] but returns an error wrapping [ErrReqEnv]
+// when a referenced variable is not set and no default value is provided.
+func ExpandStrict(env Environ, s string) (string, error) {
+	return expander{lookup: env.EnvLookup, strict: true}.expand(s)
+}
+
+// EnvExpand replaces variable references in s with values from the
+// environment. See [Expand] for details.
+func (env *Env) EnvExpand(s string) (string, error) { return Expand(env, s) }
+
+// EnvExpandStrict replaces variable references in s with values from the
+// environment. See [ExpandStrict] for details.
+func (env *Env) EnvExpandStrict(s string) (string, error) {
+	return ExpandStrict(env, s)
+}
+
+// expander expands variable references.
+type expander struct {
+	lookup func(string) (string, bool) // Variable lookup function.
+	strict bool                        // Error on undefined variables.
+}
+
+// expand expands all variable references in s.
+func (e expander) expand(s string) (string, error) {
+	if strings.IndexByte(s, '$') < 0 {
+		return s, nil
+	}
+	var buf strings.Builder
+	for len(s) > 0 {
+		idx := strings.IndexByte(s, '$')
+		if idx < 0 {
+			buf.WriteString(s)
+			break
+		}
+		buf.WriteString(s[:idx])
+		val, n, err := e.ref(s[idx:])
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(val)
+		s = s[idx+n:]
+	}
+	return buf.String(), nil
+}
+
+// ref expands the variable reference at the beginning of s, which must start
+// with "$". Returns the value and the number of consumed bytes.
+func (e expander) ref(s string) (string, int, error) {
+	if len(s) < 2 {
+		return "$", 1, nil
+	}
+	switch c := s[1]; {
+	case c == '$':
+		return "$", 2, nil
+
+	case c == '{':
+		return e.braced(s)
+
+	case isNameChar(c, true):
+		n := 2
+		for n < len(s) && isNameChar(s[n], false) {
+			n++
+		}
+		val, err := e.get(s[1:n])
+		return val, n, err
+	}
+	return "$", 1, nil
+}
+
+// braced expands the "${...}" reference at the beginning of s.
+func (e expander) braced(s string) (string, int, error) {
+	end := closingBrace(s)
+	if end < 0 {
+		return "", 0, fmt.Errorf("%w: missing '}'", ErrBadSubst)
+	}
+	body := s[2:end]
+	n := 0
+	for n < len(body) && isNameChar(body[n], n == 0) {
+		n++
+	}
+	name, rest := body[:n], body[n:]
+	if name == "" {
+		return "", 0, fmt.Errorf("%w: ${%s}", ErrBadSubst, body)
+	}
+	if rest == "" {
+		val, err := e.get(name)
+		return val, end + 1, err
+	}
+
+	colon := rest[0] == ':'
+	if colon {
+		rest = rest[1:]
+	}
+	if rest == "" {
+		return "", 0, fmt.Errorf("%w: ${%s}", ErrBadSubst, body)
+	}
+	op, word := rest[0], rest[1:]
+	val, exist := e.lookup(name)
+	empty := !exist || (colon && val == "")
+
+	var err error
+	switch op {
+	case '-':
+		if empty {
+			val, err = e.expand(word)
+		}
+	case '+':
+		val = ""
+		if !empty {
+			val, err = e.expand(word)
+		}
+	case '?':
+		if empty {
+			if val, err = e.expand(word); err != nil {
+				return "", 0, err
+			}
+			if val == "" {
+				return "", 0, envRequired(name)
+			}
+			return "", 0, fmt.Errorf("%w: %s: %s", ErrReqEnv, name, val)
+		}
+	default:
+		return "", 0, fmt.Errorf("%w: ${%s}", ErrBadSubst, body)
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return val, end + 1, nil
+}
+
+// get returns the value of the named variable. In strict mode it returns an
+// error when the variable is not set.
+func (e expander) get(name string) (string, error) {
+	val, exist := e.lookup(name)
+	if !exist && e.strict {
+		return "", envRequired(name)
+	}
+	return val, nil
+}
+
+// closingBrace returns the index of the brace closing the "${" at the
+// beginning of s or -1 if there is none.
+func closingBrace(s string) int {
+	depth := 0
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// isNameChar returns true if c may be a part of a variable name. The first
+// character of a name cannot be a digit.
+func isNameChar(c byte, first bool) bool {
+	switch {
+	case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		return true
+	case '0' <= c && c <= '9':
+		return !first
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"testing"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+// expandEnv is the environment used by [Expand] tests.
+var expandEnv = []string{"A=a", "E=", "N=1"}
+
+func Test_Expand_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		s    string
+		want string
+	}{
+		{"empty", "", ""},
+		{"no references", "abc", "abc"},
+		{"simple", "$A", "a"},
+		{"braced", "${A}", "a"},
+		{"in text", "x${A}y$A.z", "xaya.z"},
+		{"name with digits", "$N$N1", "1"},
+		{"not set", "[$X][${X}]", "[][]"},
+		{"literal dollar", "$$A", "$A"},
+		{"lone dollar", "a $ b $", "a $ b $"},
+		{"dollar before non name", "$-$1", "$-$1"},
+		{"default set", "${A:-d}", "a"},
+		{"default empty", "${E:-d}", "d"},
+		{"default not set", "${X:-d}", "d"},
+		{"default no colon empty", "${E-d}", ""},
+		{"default no colon not set", "${X-d}", "d"},
+		{"default nested", "${X:-${A}-$N}", "a-1"},
+		{"default empty word", "${X:-}", ""},
+		{"alternative set", "${A:+alt}", "alt"},
+		{"alternative empty", "${E:+alt}", ""},
+		{"alternative not set", "${X:+alt}", ""},
+		{"alternative no colon empty", "${E+alt}", "alt"},
+		{"alternative nested", "${A:+<$A>}", "<a>"},
+		{"error set", "${A:?msg}", "a"},
+		{"error no colon empty", "${E?msg}", ""},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- Given ---
+			env := NewEnv(expandEnv)
+
+			// --- When ---
+			have, err := Expand(env, tc.s)
+
+			// --- Then ---
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_Expand_errors_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		s    string
+		wErr error
+		wMsg string
+	}{
+		{
+			"error empty",
+			"${E:?must be set}",
+			ErrReqEnv,
+			"required environment variable: E: must be set",
+		},
+		{
+			"error not set",
+			"${X?must be set}",
+			ErrReqEnv,
+			"required environment variable: X: must be set",
+		},
+		{
+			"error without message",
+			"${X:?}",
+			ErrReqEnv,
+			"required environment variable: X",
+		},
+		{
+			"error message expanded",
+			"${X:?$A is set}",
+			ErrReqEnv,
+			"required environment variable: X: a is set",
+		},
+		{
+			"error in default",
+			"${X:-${Y:?}}",
+			ErrReqEnv,
+			"required environment variable: Y",
+		},
+		{
+			"missing closing brace",
+			"${A",
+			ErrBadSubst,
+			"bad substitution: missing '}'",
+		},
+		{
+			"empty name",
+			"${}",
+			ErrBadSubst,
+			"bad substitution: ${}",
+		},
+		{
+			"invalid name",
+			"${1A}",
+			ErrBadSubst,
+			"bad substitution: ${1A}",
+		},
+		{
+			"only colon",
+			"${A:}",
+			ErrBadSubst,
+			"bad substitution: ${A:}",
+		},
+		{
+			"unknown operator",
+			"${A:=x}",
+			ErrBadSubst,
+			"bad substitution: ${A:=x}",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- Given ---
+			env := NewEnv(expandEnv)
+
+			// --- When ---
+			have, err := Expand(env, tc.s)
+
+			// --- Then ---
+			assert.ErrorIs(t, tc.wErr, err)
+			assert.ErrorEqual(t, tc.wMsg, err)
+			assert.Equal(t, "", have)
+		})
+	}
+}
+
+func Test_ExpandStrict(t *testing.T) {
+	t.Run("defined", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(expandEnv)
+
+		// --- When ---
+		have, err := ExpandStrict(env, "$A-${E}")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "a-", have)
+	})
+
+	t.Run("undefined with default", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(expandEnv)
+
+		// --- When ---
+		have, err := ExpandStrict(env, "${X:-d}${X:+alt}")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "d", have)
+	})
+
+	t.Run("error - undefined", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(expandEnv)
+
+		// --- When ---
+		have, err := ExpandStrict(env, "$A-$X")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReqEnv, err)
+		assert.ErrorEqual(t, "required environment variable: X", err)
+		assert.Equal(t, "", have)
+	})
+
+	t.Run("error - undefined braced", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(expandEnv)
+
+		// --- When ---
+		have, err := ExpandStrict(env, "${X}")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReqEnv, err)
+		assert.Equal(t, "", have)
+	})
+}
+
+func Test_Env_EnvExpand(t *testing.T) {
+	// --- Given ---
+	env := NewEnv(expandEnv)
+
+	// --- When ---
+	have, err := env.EnvExpand("$A-$X")
+
+	// --- Then ---
+	assert.NoError(t, err)
+	assert.Equal(t, "a-", have)
+}
+
+func Test_Env_EnvExpandStrict(t *testing.T) {
+	// --- Given ---
+	env := NewEnv(expandEnv)
+
+	// --- When ---
+	have, err := env.EnvExpandStrict("$A-$X")
+
+	// --- Then ---
+	assert.ErrorIs(t, ErrReqEnv, err)
+	assert.Equal(t, "", have)
+}
+
+func Test_closingBrace_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		s    string
+		want int
+	}{
+		{"simple", "${A}", 3},
+		{"nested", "${A:-${B}}x", 9},
+		{"not closed", "${A", -1},
+		{"not closed nested", "${A:-${B}", -1},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have := closingBrace(tc.s)
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
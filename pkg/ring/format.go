@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// EnvFormat represents the environment serialization format.
+type EnvFormat string
+
+// Environment serialization formats.
+const (
+	// FormatPOSIX represents POSIX shell "export KEY='value'" lines.
+	FormatPOSIX EnvFormat = "posix"
+
+	// FormatFish represents fish shell "set -gx KEY 'value'" lines.
+	FormatFish EnvFormat = "fish"
+
+	// FormatDotenv represents dotenv KEY="value" lines, see [ParseDotenv].
+	FormatDotenv EnvFormat = "dotenv"
+
+	// FormatJSON represents a JSON object with string values.
+	FormatJSON EnvFormat = "json"
+
+	// FormatNull represents "KEY=value" entries terminated by the NUL byte,
+	// as used by /proc/<pid>/environ and "env -0".
+	FormatNull EnvFormat = "null"
+)
+
+// EnvExport writes the environment to the writer in the given format, with
+// variables sorted by name. Values of sensitive variables are written as is.
+//
+// For the shell and dotenv formats, the variable names must be valid shell
+// variable names, otherwise an error wrapping [ErrInvEnv] is returned and
+// nothing is written. The same error is returned for the JSON format when
+// a variable name or value is not valid UTF-8, and for the NUL format when
+// it contains the NUL byte. An error wrapping [ErrFormat] is returned for
+// unknown formats.
+func (env *Env) EnvExport(w io.Writer, format EnvFormat) error {
+	all := env.EnvAll()
+	var line func(key, val string) string
+	switch format {
+	case FormatPOSIX:
+		line = func(key, val string) string {
+			return "export " + key + "=" + quotePOSIX(val) + "\n"
+		}
+	case FormatFish:
+		line = func(key, val string) string {
+			return "set -gx " + key + " " + quoteFish(val) + "\n"
+		}
+	case FormatDotenv:
+		line = func(key, val string) string {
+			return key + "=" + quoteDotenv(val) + "\n"
+		}
+	case FormatJSON:
+		for _, entry := range all {
+			if !utf8.ValidString(entry) {
+				key, _, _ := strings.Cut(entry, "=")
+				return fmt.Errorf("%w: %s: invalid UTF-8", ErrInvEnv, key)
+			}
+		}
+		data, err := json.Marshal(EnvSplit(all))
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	case FormatNull:
+		line = func(key, val string) string { return key + "=" + val + "\x00" }
+	default:
+		return fmt.Errorf("%w: %s", ErrFormat, format)
+	}
+
+	buf := &bytes.Buffer{}
+	for _, entry := range all {
+		key, val, _ := strings.Cut(entry, "=")
+		if format != FormatNull && !isShellName(key) {
+			return fmt.Errorf("%w: %s: invalid name", ErrInvEnv, key)
+		}
+		if format == FormatNull && strings.IndexByte(entry, 0) >= 0 {
+			return fmt.Errorf("%w: %q: contains NUL", ErrInvEnv, key)
+		}
+		buf.WriteString(line(key, val))
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ParseEnvFormat parses data in the given format from the reader and returns
+// variables it defines. It accepts data written by [Env.EnvExport] for
+// [FormatPOSIX], [FormatDotenv], [FormatJSON] and [FormatNull] formats. The
+// [FormatPOSIX] parser understands only "export KEY='value'" lines with
+// values in single quotes, and "\'" sequences between them, and
+// [FormatDotenv] data is parsed with [ParseDotenv] without a lookup
+// function.
+//
+// Syntax errors wrap [ErrSyntax] or [ErrDotenv]. An error wrapping
+// [ErrFormat] is returned for unknown formats or formats without a parser.
+func ParseEnvFormat(r io.Reader, format EnvFormat) (map[string]string, error) {
+	switch format {
+	case FormatDotenv:
+		return ParseDotenv(r, nil)
+	case FormatPOSIX, FormatJSON, FormatNull:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrFormat, format)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatPOSIX:
+		return parsePOSIX(string(data))
+	case FormatJSON:
+		ret := make(map[string]string)
+		if err = json.Unmarshal(data, &ret); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrSyntax, err)
+		}
+		return ret, nil
+	default:
+		return parseNull(data)
+	}
+}
+
+// quotePOSIX quotes the value for POSIX shells with single quotes. Each
+// single quote in the value closes the quoted string, is written escaped
+// with a backslash and opens a new quoted string.
+func quotePOSIX(val string) string {
+	return "'" + strings.ReplaceAll(val, "'", `'\''`) + "'"
+}
+
+// quoteFish quotes the value for the fish shell with single quotes, in which
+// backslashes and single quotes are escaped with a backslash.
+func quoteFish(val string) string {
+	val = strings.ReplaceAll(val, `\`, `\\`)
+	return "'" + strings.ReplaceAll(val, "'", `\'`) + "'"
+}
+
+// dotenvEscaper escapes double-quoted dotenv values, see [ParseDotenv].
+var dotenvEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	`$`, `\$`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+)
+
+// quoteDotenv quotes the value with double quotes, see [ParseDotenv].
+func quoteDotenv(val string) string {
+	return `"` + dotenvEscaper.Replace(val) + `"`
+}
+
+// parsePOSIX parses "export KEY='value'" lines.
+func parsePOSIX(src string) (map[string]string, error) {
+	ret := make(map[string]string)
+	line := 1
+	errorf := func(format string, args ...any) error {
+		return fmt.Errorf(
+			"%w: line %d: %s",
+			ErrSyntax,
+			line,
+			fmt.Sprintf(format, args...),
+		)
+	}
+	for len(src) > 0 {
+		if src[0] == '\n' {
+			line++
+			src = src[1:]
+			continue
+		}
+		rest, ok := strings.CutPrefix(src, "export ")
+		if !ok {
+			return nil, errorf("expected export")
+		}
+		key, rest, ok := strings.Cut(rest, "=")
+		if !ok || !isShellName(key) {
+			return nil, errorf("invalid variable name")
+		}
+		var val strings.Builder
+		for len(rest) > 0 && rest[0] != '\n' {
+			switch {
+			case rest[0] == '\'':
+				end := strings.IndexByte(rest[1:], '\'')
+				if end < 0 {
+					return nil, errorf("unterminated single-quoted value")
+				}
+				val.WriteString(rest[1 : end+1])
+				line += strings.Count(rest[1:end+1], "\n")
+				rest = rest[end+2:]
+			case strings.HasPrefix(rest, `\'`):
+				val.WriteByte('\'')
+				rest = rest[2:]
+			default:
+				return nil, errorf("unexpected character %q", rest[0])
+			}
+		}
+		ret[key] = val.String()
+		src = rest
+	}
+	return ret, nil
+}
+
+// parseNull parses "KEY=value" entries terminated by the NUL byte. The last
+// entry may be not terminated.
+func parseNull(data []byte) (map[string]string, error) {
+	ret := make(map[string]string)
+	data = bytes.TrimSuffix(data, []byte{0})
+	if len(data) == 0 {
+		return ret, nil
+	}
+	for i, entry := range bytes.Split(data, []byte{0}) {
+		key, val, ok := strings.Cut(string(entry), "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w: entry %d: no '='", ErrSyntax, i+1)
+		}
+		ret[key] = val
+	}
+	return ret, nil
+}
+
+// isShellName returns true if the name is a valid shell variable name.
+func isShellName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isNameChar(name[i], i == 0) {
+			return false
+		}
+	}
+	return true
+}
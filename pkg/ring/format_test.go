@@ -0,0 +1,463 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+// formatEnv is the environment with values requiring quoting used by format
+// tests.
+var formatEnv = map[string]string{
+	"A":     "plain",
+	"B":     "it's",
+	"C":     `a "b" \c $D`,
+	"EMPTY": "",
+	"ML":    "line1\nline2\r\n\tx",
+}
+
+func Test_Env_EnvExport(t *testing.T) {
+	t.Run("posix", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		env.EnvSetFrom(formatEnv)
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := env.EnvExport(buf, FormatPOSIX)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := "" +
+			"export A='plain'\n" +
+			"export B='it'\\''s'\n" +
+			"export C='a \"b\" \\c $D'\n" +
+			"export EMPTY=''\n" +
+			"export ML='line1\nline2\r\n\tx'\n"
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("fish", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		env.EnvSetFrom(formatEnv)
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := env.EnvExport(buf, FormatFish)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := "" +
+			"set -gx A 'plain'\n" +
+			"set -gx B 'it\\'s'\n" +
+			"set -gx C 'a \"b\" \\\\c $D'\n" +
+			"set -gx EMPTY ''\n" +
+			"set -gx ML 'line1\nline2\r\n\tx'\n"
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("dotenv", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		env.EnvSetFrom(formatEnv)
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := env.EnvExport(buf, FormatDotenv)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := "" +
+			"A=\"plain\"\n" +
+			"B=\"it's\"\n" +
+			"C=\"a \\\"b\\\" \\\\c \\$D\"\n" +
+			"EMPTY=\"\"\n" +
+			"ML=\"line1\\nline2\\r\\n\\tx\"\n"
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"B=2", "A=<1>"})
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := env.EnvExport(buf, FormatJSON)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := "{\"A\":\"\\u003c1\\u003e\",\"B\":\"2\"}\n"
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("json empty", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := env.EnvExport(buf, FormatJSON)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "{}\n", buf.String())
+	})
+
+	t.Run("null", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"B=2", "A=1=1", "C-D=x\ny"})
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := env.EnvExport(buf, FormatNull)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "A=1=1\x00B=2\x00C-D=x\ny\x00", buf.String())
+	})
+
+	t.Run("empty environment", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := env.EnvExport(buf, FormatPOSIX)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "", buf.String())
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1", "B-C=2"})
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := env.EnvExport(buf, FormatPOSIX)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "invalid environment variable: B-C: invalid name"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Equal(t, "", buf.String())
+	})
+
+	t.Run("error - json invalid UTF-8", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1", "B=\xff"})
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := env.EnvExport(buf, FormatJSON)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "invalid environment variable: B: invalid UTF-8"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Equal(t, "", buf.String())
+	})
+
+	t.Run("error - null NUL in value", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1", "B=x\x00y"})
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := env.EnvExport(buf, FormatNull)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "invalid environment variable: \"B\": contains NUL"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Equal(t, "", buf.String())
+	})
+
+	t.Run("error - null NUL in name", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A\x00B=1"})
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := env.EnvExport(buf, FormatNull)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "invalid environment variable: \"A\\x00B\": contains NUL"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Equal(t, "", buf.String())
+	})
+
+	t.Run("error - unknown format", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := env.EnvExport(buf, "abc")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrFormat, err)
+		assert.ErrorEqual(t, "unsupported environment format: abc", err)
+	})
+
+	t.Run("error - write", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+
+		// --- When ---
+		err := env.EnvExport(errWriter{}, FormatDotenv)
+
+		// --- Then ---
+		assert.ErrorIs(t, errWrite, err)
+	})
+
+	t.Run("error - write json", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+
+		// --- When ---
+		err := env.EnvExport(errWriter{}, FormatJSON)
+
+		// --- Then ---
+		assert.ErrorIs(t, errWrite, err)
+	})
+}
+
+func Test_EnvExport_round_trip_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		format EnvFormat
+	}{
+		{"posix", FormatPOSIX},
+		{"dotenv", FormatDotenv},
+		{"json", FormatJSON},
+		{"null", FormatNull},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- Given ---
+			env := NewEnv(nil)
+			env.EnvSetFrom(formatEnv)
+			buf := &bytes.Buffer{}
+			assert.NoError(t, env.EnvExport(buf, tc.format))
+
+			// --- When ---
+			have, err := ParseEnvFormat(buf, tc.format)
+
+			// --- Then ---
+			assert.NoError(t, err)
+			assert.Equal(t, formatEnv, have)
+		})
+	}
+}
+
+func Test_EnvExport_round_trip_null_NUL(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"A=1", "B=x\x00C=y", "D=2"})
+	buf := &bytes.Buffer{}
+
+	// --- When ---
+	err := env.EnvExport(buf, FormatNull)
+
+	// --- Then ---
+	assert.ErrorIs(t, ErrInvEnv, err)
+	have, err := ParseEnvFormat(buf, FormatNull)
+	assert.NoError(t, err)
+	assert.Empty(t, have)
+}
+
+func Test_ParseEnvFormat(t *testing.T) {
+	t.Run("posix", func(t *testing.T) {
+		// --- Given ---
+		src := "export A='a'\\''b'\n\nexport B='x\ny'\nexport C=\n"
+
+		// --- When ---
+		have, err := ParseEnvFormat(strings.NewReader(src), FormatPOSIX)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := map[string]string{"A": "a'b", "B": "x\ny", "C": ""}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("dotenv", func(t *testing.T) {
+		// --- Given ---
+		src := "A=1\nB=${A}2\n"
+
+		// --- When ---
+		have, err := ParseEnvFormat(strings.NewReader(src), FormatDotenv)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"A": "1", "B": "12"}, have)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		// --- Given ---
+		src := `{"A": "1", "B": ""}`
+
+		// --- When ---
+		have, err := ParseEnvFormat(strings.NewReader(src), FormatJSON)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"A": "1", "B": ""}, have)
+	})
+
+	t.Run("null", func(t *testing.T) {
+		// --- Given ---
+		src := "A=1\x00B=\x00C=x=y"
+
+		// --- When ---
+		have, err := ParseEnvFormat(strings.NewReader(src), FormatNull)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := map[string]string{"A": "1", "B": "", "C": "x=y"}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("null empty", func(t *testing.T) {
+		// --- When ---
+		have, err := ParseEnvFormat(strings.NewReader(""), FormatNull)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Len(t, 0, have)
+	})
+
+	t.Run("error - read", func(t *testing.T) {
+		// --- When ---
+		have, err := ParseEnvFormat(errReader{}, FormatJSON)
+
+		// --- Then ---
+		assert.ErrorEqual(t, "read", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - fish", func(t *testing.T) {
+		// --- When ---
+		have, err := ParseEnvFormat(strings.NewReader(""), FormatFish)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrFormat, err)
+		assert.ErrorEqual(t, "unsupported environment format: fish", err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_ParseEnvFormat_errors_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		format EnvFormat
+		src    string
+		wMsg   string
+	}{
+		{
+			"posix without export",
+			FormatPOSIX,
+			"A='1'",
+			"invalid environment syntax: line 1: expected export",
+		},
+		{
+			"posix invalid name",
+			FormatPOSIX,
+			"export A='1'\nexport B-C='2'",
+			"invalid environment syntax: line 2: invalid variable name",
+		},
+		{
+			"posix no equal sign",
+			FormatPOSIX,
+			"export A",
+			"invalid environment syntax: line 1: invalid variable name",
+		},
+		{
+			"posix unterminated",
+			FormatPOSIX,
+			"export A='1\n",
+			"invalid environment syntax: line 1: " +
+				"unterminated single-quoted value",
+		},
+		{
+			"posix unquoted",
+			FormatPOSIX,
+			"export A='x'\nexport B='a\nb'c",
+			"invalid environment syntax: line 3: unexpected character 'c'",
+		},
+		{
+			"json",
+			FormatJSON,
+			`{"A": "1"`,
+			"invalid environment syntax: unexpected end of JSON input",
+		},
+		{
+			"null no equal sign",
+			FormatNull,
+			"A=1\x00B\x00",
+			"invalid environment syntax: entry 2: no '='",
+		},
+		{
+			"null empty entry",
+			FormatNull,
+			"A=1\x00\x00",
+			"invalid environment syntax: entry 2: no '='",
+		},
+		{
+			"null empty name",
+			FormatNull,
+			"=1",
+			"invalid environment syntax: entry 1: no '='",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have, err := ParseEnvFormat(strings.NewReader(tc.src), tc.format)
+
+			// --- Then ---
+			assert.ErrorIs(t, ErrSyntax, err)
+			assert.ErrorEqual(t, tc.wMsg, err)
+			assert.Nil(t, have)
+		})
+	}
+}
+
+func Test_isShellName_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		name string
+		want bool
+	}{
+		{"empty", "", false},
+		{"letter", "A", true},
+		{"underscore", "_", true},
+		{"with digits", "A_1", true},
+		{"leading digit", "1A", false},
+		{"dash", "A-B", false},
+		{"dot", "A.B", false},
+		{"space", "A B", false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have := isShellName(tc.name)
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// EnvFreeze freezes the environment. Any attempt to modify a frozen
+// environment with [Env.EnvSet], [Env.EnvUnset] or methods using them panics
+// with an error wrapping [ErrReadOnly]. Freezing cannot be undone, use
+// [Env.EnvClone] to get a modifiable copy.
+//
+// Freezing a layered environment does not freeze its parent, changes made to
+// the parent remain visible in the frozen layer.
+func (env *Env) EnvFreeze() {
+	defer env.lock()()
+	env.frozen = true
+}
+
+// EnvFrozen returns true if the environment is frozen.
+func (env *Env) EnvFrozen() bool {
+	defer env.rlock()()
+	return env.frozen
+}
+
+// mustWritable panics if the environment is frozen. The caller must hold
+// the write lock.
+func (env *Env) mustWritable(op EnvOp, key string) {
+	if env.frozen {
+		panic(readOnly(op, key))
+	}
+}
+
+// readOnly returns an error wrapping [ErrReadOnly] for the operation.
+func readOnly(op EnvOp, key string) error {
+	return fmt.Errorf("%w: %s %s", ErrReadOnly, op, key)
+}
+
+var _ Environ = &EnvReadOnly{} // Compile time check.
+
+// EnvReadOnly is a read-only [Environ] view of an environment. Unlike the
+// frozen [Env], attempts to modify the environment through the view do not
+// panic, they are ignored and reported by [EnvReadOnly.Err]. It is safe for
+// concurrent use as long as the underlying environment is.
+type EnvReadOnly struct {
+	env  Environ    // The underlying environment.
+	errs []error    // Rejected modification attempts.
+	mx   sync.Mutex // Guards errs.
+}
+
+// NewEnvReadOnly returns a new [EnvReadOnly] view of the environment.
+func NewEnvReadOnly(env Environ) *EnvReadOnly {
+	return &EnvReadOnly{env: env}
+}
+
+// EnvLookup retrieves the value of the variable named by the key.
+func (ro *EnvReadOnly) EnvLookup(key string) (string, bool) {
+	return ro.env.EnvLookup(key)
+}
+
+// EnvGet retrieves the value of the variable named by the key.
+func (ro *EnvReadOnly) EnvGet(key string) string { return ro.env.EnvGet(key) }
+
+// EnvSet does not modify the environment, the attempt is reported by
+// [EnvReadOnly.Err].
+func (ro *EnvReadOnly) EnvSet(key, _ string) { ro.reject(OpSet, key) }
+
+// EnvUnset does not modify the environment, the attempt is reported by
+// [EnvReadOnly.Err].
+func (ro *EnvReadOnly) EnvUnset(key string) { ro.reject(OpUnset, key) }
+
+// EnvAll returns all variables of the underlying environment.
+func (ro *EnvReadOnly) EnvAll() []string { return ro.env.EnvAll() }
+
+// Err returns all rejected modification attempts joined with [errors.Join],
+// each wrapping [ErrReadOnly]. Returns nil if there were none.
+func (ro *EnvReadOnly) Err() error {
+	ro.mx.Lock()
+	defer ro.mx.Unlock()
+	return errors.Join(ro.errs...)
+}
+
+// reject records rejected modification attempt.
+func (ro *EnvReadOnly) reject(op EnvOp, key string) {
+	ro.mx.Lock()
+	defer ro.mx.Unlock()
+	ro.errs = append(ro.errs, readOnly(op, key))
+}
@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+func Test_Env_EnvFreeze(t *testing.T) {
+	t.Run("freeze", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+
+		// --- When ---
+		env.EnvFreeze()
+
+		// --- Then ---
+		assert.True(t, env.frozen)
+		assert.Equal(t, "1", env.EnvGet("A"))
+		assert.Equal(t, []string{"A=1"}, env.EnvAll())
+	})
+
+	t.Run("set panics", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+		env.EnvFreeze()
+
+		// --- When ---
+		msg := assert.PanicMsg(t, "read-only environment: set B", func() {
+			env.EnvSet("B", "2")
+		})
+
+		// --- Then ---
+		assert.NotNil(t, msg)
+		assert.Equal(t, []string{"A=1"}, env.EnvAll())
+	})
+
+	t.Run("unset panics", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+		env.EnvFreeze()
+
+		// --- When ---
+		msg := assert.PanicMsg(t, "read-only environment: unset A", func() {
+			env.EnvUnset("A")
+		})
+
+		// --- Then ---
+		assert.NotNil(t, msg)
+		assert.Equal(t, []string{"A=1"}, env.EnvAll())
+	})
+
+	t.Run("panic value wraps ErrReadOnly", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		env.EnvFreeze()
+
+		// --- When ---
+		var have any
+		func() {
+			defer func() { have = recover() }()
+			env.EnvSetFrom(map[string]string{"A": "1"})
+		}()
+
+		// --- Then ---
+		err, _ := have.(error)
+		assert.ErrorIs(t, ErrReadOnly, err)
+	})
+
+	t.Run("layered sees parent changes", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv([]string{"A=1"})
+		env := NewEnvLayer(parent)
+		env.EnvFreeze()
+
+		// --- When ---
+		parent.EnvSet("A", "2")
+
+		// --- Then ---
+		assert.Equal(t, "2", env.EnvGet("A"))
+		assert.False(t, parent.EnvFrozen())
+	})
+
+	t.Run("load dotenv", func(t *testing.T) {
+		// --- Given ---
+		fsys := fstest.MapFS{".env": &fstest.MapFile{Data: []byte("A=1")}}
+		env := NewEnv(nil)
+		env.EnvFreeze()
+
+		// --- When ---
+		err := env.EnvLoadDotenv(fsys, Override, ".env")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReadOnly, err)
+		assert.Nil(t, env.EnvAll())
+	})
+}
+
+func Test_Env_EnvFrozen(t *testing.T) {
+	t.Run("not frozen", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have := env.EnvFrozen()
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+
+	t.Run("frozen", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		env.EnvFreeze()
+
+		// --- When ---
+		have := env.EnvFrozen()
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+}
+
+func Test_NewEnvReadOnly(t *testing.T) {
+	// --- Given ---
+	env := NewEnv(nil)
+
+	// --- When ---
+	have := NewEnvReadOnly(env)
+
+	// --- Then ---
+	assert.Same(t, env, have.env)
+	assert.Nil(t, have.errs)
+}
+
+func Test_EnvReadOnly_EnvLookup(t *testing.T) {
+	// --- Given ---
+	ro := NewEnvReadOnly(NewEnv([]string{"A=1"}))
+
+	// --- When ---
+	have, exist := ro.EnvLookup("A")
+
+	// --- Then ---
+	assert.True(t, exist)
+	assert.Equal(t, "1", have)
+}
+
+func Test_EnvReadOnly_EnvGet(t *testing.T) {
+	// --- Given ---
+	ro := NewEnvReadOnly(NewEnv([]string{"A=1"}))
+
+	// --- When ---
+	have := ro.EnvGet("A")
+
+	// --- Then ---
+	assert.Equal(t, "1", have)
+}
+
+func Test_EnvReadOnly_EnvSet(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"A=1"})
+	ro := NewEnvReadOnly(env)
+
+	// --- When ---
+	ro.EnvSet("A", "2")
+
+	// --- Then ---
+	assert.Equal(t, []string{"A=1"}, env.EnvAll())
+	err := ro.Err()
+	assert.ErrorIs(t, ErrReadOnly, err)
+	assert.ErrorEqual(t, "read-only environment: set A", err)
+}
+
+func Test_EnvReadOnly_EnvUnset(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"A=1"})
+	ro := NewEnvReadOnly(env)
+
+	// --- When ---
+	ro.EnvUnset("A")
+
+	// --- Then ---
+	assert.Equal(t, []string{"A=1"}, env.EnvAll())
+	err := ro.Err()
+	assert.ErrorIs(t, ErrReadOnly, err)
+	assert.ErrorEqual(t, "read-only environment: unset A", err)
+}
+
+func Test_EnvReadOnly_EnvAll(t *testing.T) {
+	// --- Given ---
+	ro := NewEnvReadOnly(NewEnv([]string{"A=1"}))
+
+	// --- When ---
+	have := ro.EnvAll()
+
+	// --- Then ---
+	assert.Equal(t, []string{"A=1"}, have)
+}
+
+func Test_EnvReadOnly_Err(t *testing.T) {
+	t.Run("no attempts", func(t *testing.T) {
+		// --- Given ---
+		ro := NewEnvReadOnly(NewEnv(nil))
+
+		// --- When ---
+		err := ro.Err()
+
+		// --- Then ---
+		assert.NoError(t, err)
+	})
+
+	t.Run("all attempts", func(t *testing.T) {
+		// --- Given ---
+		ro := NewEnvReadOnly(NewEnv(nil))
+		ro.EnvSet("A", "1")
+		ro.EnvUnset("B")
+
+		// --- When ---
+		err := ro.Err()
+
+		// --- Then ---
+		wMsg := "read-only environment: set A\n" +
+			"read-only environment: unset B"
+		assert.ErrorEqual(t, wMsg, err)
+	})
+
+	t.Run("layer on top of read-only view", func(t *testing.T) {
+		// --- Given ---
+		ro := NewEnvReadOnly(NewEnv([]string{"A=1"}))
+		env := NewEnvLayer(ro)
+
+		// --- When ---
+		env.EnvSet("A", "2")
+
+		// --- Then ---
+		assert.NoError(t, ro.Err())
+		assert.Equal(t, "1", ro.EnvGet("A"))
+	})
+}
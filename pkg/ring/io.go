@@ -6,6 +6,7 @@ package ring
 import (
 	"io"
 	"os"
+	"sync"
 )
 
 // Streamer defines an interface for accessing a program's standard I/O streams.
@@ -22,6 +23,9 @@ type IO struct {
 	stdin  io.Reader // Program standard input.
 	stdout io.Writer // Program standard output.
 	stderr io.Writer // Program standard error.
+
+	// Guards the streams, nil when not concurrent, see [IO.IOConcurrent].
+	mx *sync.RWMutex
 }
 
 // NewIO returns a new instance of the IO struct with [os.Stdin], [os.Stdout],
@@ -36,28 +40,48 @@ func NewIO() *IO {
 }
 
 // Stdin returns the standard input to use for a program.
-func (ios *IO) Stdin() io.Reader { return ios.stdin }
+func (ios *IO) Stdin() io.Reader {
+	defer rlockMutex(ios.mx)()
+	return ios.stdin
+}
 
 // Stdout returns the standard output to use for a program.
-func (ios *IO) Stdout() io.Writer { return ios.stdout }
+func (ios *IO) Stdout() io.Writer {
+	defer rlockMutex(ios.mx)()
+	return ios.stdout
+}
 
 // Stderr returns the standard error to use for a program.
-func (ios *IO) Stderr() io.Writer { return ios.stderr }
+func (ios *IO) Stderr() io.Writer {
+	defer rlockMutex(ios.mx)()
+	return ios.stderr
+}
 
 // SetStdin returns [IO] with the given standard input.
-func (ios *IO) SetStdin(sin io.Reader) { ios.stdin = sin }
+func (ios *IO) SetStdin(sin io.Reader) {
+	defer lockMutex(ios.mx)()
+	ios.stdin = sin
+}
 
 // SetStdout returns [IO] with the given standard output.
-func (ios *IO) SetStdout(sout io.Writer) { ios.stdout = sout }
+func (ios *IO) SetStdout(sout io.Writer) {
+	defer lockMutex(ios.mx)()
+	ios.stdout = sout
+}
 
 // SetStderr returns [IO] with the given standard error.
-func (ios *IO) SetStderr(eout io.Writer) { ios.stderr = eout }
+func (ios *IO) SetStderr(eout io.Writer) {
+	defer lockMutex(ios.mx)()
+	ios.stderr = eout
+}
 
 // IOClone creates a copy of the current [IO] instance with identical streams.
 func (ios *IO) IOClone() *IO {
+	defer rlockMutex(ios.mx)()
 	return &IO{
 		stdin:  ios.stdin,
 		stdout: ios.stdout,
 		stderr: ios.stderr,
+		mx:     newMutex(ios.mx != nil),
 	}
 }
@@ -97,5 +97,6 @@ func Test_IO_IOClone(t *testing.T) {
 	assert.Same(t, ios.stdin, have.stdin)
 	assert.Same(t, ios.stdout, have.stdout)
 	assert.Same(t, ios.stderr, have.stderr)
-	assert.Fields(t, 3, IO{})
+	assert.Nil(t, have.mx)
+	assert.Fields(t, 4, IO{})
 }
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+)
+
+// LoadLocation returns the [time.Location] with the given name, for example,
+// "Europe/Warsaw". The name "" and "UTC" return [time.UTC], and "Local"
+// returns [time.Local]. A leading ":", allowed in the TZ environment
+// variable, is ignored.
+//
+// The location is loaded from the tz database in the filesystem, where each
+// location is a file in TZif format named after the location, like in the
+// "/usr/share/zoneinfo" directory. Use [embed.FS] to embed the database in
+// the program or [zip.Reader] to read the "zoneinfo.zip" file shipped with
+// Go. When tzdata is nil, [time.LoadLocation] is used.
+//
+// An error wrapping [ErrLocation] is returned when the location cannot be
+// loaded.
+func LoadLocation(name string, tzdata fs.FS) (*time.Location, error) {
+	name = strings.TrimPrefix(name, ":")
+	switch name {
+	case "", "UTC":
+		return time.UTC, nil
+	case "Local":
+		return time.Local, nil
+	}
+
+	if tzdata == nil {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrLocation, name)
+		}
+		return loc, nil
+	}
+	data, err := fs.ReadFile(tzdata, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrLocation, name, err)
+	}
+	loc, err := time.LoadLocationFromTZData(name, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrLocation, name, err)
+	}
+	return loc, nil
+}
+
+// WithLocation configures a [Ring] with the location used to present time,
+// see [Ring.Now]. The nil location means [time.UTC].
+func WithLocation(loc *time.Location) Option {
+	return func(rng *Ring) { rng.SetLocation(loc) }
+}
+
+// Location returns the location used to present time, by default
+// [time.UTC].
+func (rng *Ring) Location() *time.Location {
+	defer rlockMutex(rng.mx)()
+	return rng.loc
+}
+
+// SetLocation sets the location used to present time. The nil location
+// means [time.UTC].
+func (rng *Ring) SetLocation(loc *time.Location) *Ring {
+	if loc == nil {
+		loc = time.UTC
+	}
+	defer lockMutex(rng.mx)()
+	rng.loc = loc
+	return rng
+}
+
+// LoadLocation sets the location named by the TZ environment variable of the
+// [Ring], loading it from the tz database in the filesystem. See
+// [LoadLocation] for details. When TZ is not set or empty, [time.UTC] is
+// used. On error, the location is not changed.
+func (rng *Ring) LoadLocation(tzdata fs.FS) error {
+	loc, err := LoadLocation(rng.EnvGet("TZ"), tzdata)
+	if err != nil {
+		return err
+	}
+	rng.SetLocation(loc)
+	return nil
+}
+
+// Now returns the current time of the [Ring] clock in the [Ring] location.
+func (rng *Ring) Now() time.Time { return rng.clock.Now().In(rng.Location()) }
+
+// In returns the time in the [Ring] location.
+func (rng *Ring) In(tim time.Time) time.Time { return tim.In(rng.Location()) }
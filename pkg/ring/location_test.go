@@ -0,0 +1,283 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+// tzdata is the tz database with a few locations used in tests.
+var tzdata = os.DirFS("testdata/zoneinfo")
+
+// tzTime is the time used by location tests.
+var tzTime = time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func Test_LoadLocation(t *testing.T) {
+	t.Run("from filesystem", func(t *testing.T) {
+		// --- When ---
+		have, err := LoadLocation("Europe/Warsaw", tzdata)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "Europe/Warsaw", have.String())
+		assert.Equal(t, "04:04:05 CET", tzTime.In(have).Format("15:04:05 MST"))
+	})
+
+	t.Run("from zip", func(t *testing.T) {
+		// --- Given ---
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		w := must.Value(zw.Create("America/New_York"))
+		data := must.Value(fs.ReadFile(tzdata, "America/New_York"))
+		must.Value(w.Write(data))
+		must.Nil(zw.Close())
+		rd := bytes.NewReader(buf.Bytes())
+		zr := must.Value(zip.NewReader(rd, int64(buf.Len())))
+
+		// --- When ---
+		have, err := LoadLocation("America/New_York", zr)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "America/New_York", have.String())
+		assert.Equal(t, "22:04 EST", tzTime.In(have).Format("15:04 MST"))
+	})
+
+	t.Run("leading colon", func(t *testing.T) {
+		// --- When ---
+		have, err := LoadLocation(":Europe/Warsaw", tzdata)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "Europe/Warsaw", have.String())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		// --- When ---
+		have, err := LoadLocation("", tzdata)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Same(t, time.UTC, have)
+	})
+
+	t.Run("UTC", func(t *testing.T) {
+		// --- When ---
+		have, err := LoadLocation("UTC", nil)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Same(t, time.UTC, have)
+	})
+
+	t.Run("Local", func(t *testing.T) {
+		// --- When ---
+		have, err := LoadLocation("Local", tzdata)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Same(t, time.Local, have)
+	})
+
+	t.Run("error - not in filesystem", func(t *testing.T) {
+		// --- When ---
+		have, err := LoadLocation("Mars/Olympus", tzdata)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrLocation, err)
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - invalid data", func(t *testing.T) {
+		// --- Given ---
+		fsys := fstest.MapFS{"Bad/Zone": {Data: []byte("abc")}}
+
+		// --- When ---
+		have, err := LoadLocation("Bad/Zone", fsys)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrLocation, err)
+		assert.ErrorContain(t, "unknown time zone: Bad/Zone: ", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - not in system database", func(t *testing.T) {
+		// --- When ---
+		have, err := LoadLocation("Mars/Olympus", nil)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrLocation, err)
+		assert.ErrorEqual(t, "unknown time zone: Mars/Olympus", err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_WithLocation(t *testing.T) {
+	t.Run("location", func(t *testing.T) {
+		// --- Given ---
+		rng := &Ring{}
+		loc := must.Value(LoadLocation("Europe/Warsaw", tzdata))
+
+		// --- When ---
+		WithLocation(loc)(rng)
+
+		// --- Then ---
+		assert.Same(t, loc, rng.loc)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- Given ---
+		rng := &Ring{}
+
+		// --- When ---
+		WithLocation(nil)(rng)
+
+		// --- Then ---
+		assert.Same(t, time.UTC, rng.loc)
+	})
+}
+
+func Test_Ring_Location(t *testing.T) {
+	// --- Given ---
+	loc := must.Value(LoadLocation("Europe/Warsaw", tzdata))
+	rng := New(WithLocation(loc))
+
+	// --- When ---
+	have := rng.Location()
+
+	// --- Then ---
+	assert.Same(t, loc, have)
+}
+
+func Test_Ring_SetLocation(t *testing.T) {
+	t.Run("location", func(t *testing.T) {
+		// --- Given ---
+		rng := New()
+		loc := must.Value(LoadLocation("Europe/Warsaw", tzdata))
+
+		// --- When ---
+		have := rng.SetLocation(loc)
+
+		// --- Then ---
+		assert.Same(t, rng, have)
+		assert.Same(t, loc, rng.loc)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- Given ---
+		loc := must.Value(LoadLocation("Europe/Warsaw", tzdata))
+		rng := New(WithLocation(loc))
+
+		// --- When ---
+		rng.SetLocation(nil)
+
+		// --- Then ---
+		assert.Same(t, time.UTC, rng.loc)
+	})
+}
+
+func Test_Ring_LoadLocation(t *testing.T) {
+	t.Run("from TZ", func(t *testing.T) {
+		// --- Given ---
+		rng := New(WithEnv([]string{"TZ=America/New_York"}))
+
+		// --- When ---
+		err := rng.LoadLocation(tzdata)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "America/New_York", rng.Location().String())
+	})
+
+	t.Run("TZ not set", func(t *testing.T) {
+		// --- Given ---
+		loc := must.Value(LoadLocation("Europe/Warsaw", tzdata))
+		rng := New(WithEnv(nil), WithLocation(loc))
+
+		// --- When ---
+		err := rng.LoadLocation(tzdata)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Same(t, time.UTC, rng.Location())
+	})
+
+	t.Run("error - unknown location", func(t *testing.T) {
+		// --- Given ---
+		loc := must.Value(LoadLocation("Europe/Warsaw", tzdata))
+		rng := New(WithEnv([]string{"TZ=Mars/Olympus"}), WithLocation(loc))
+
+		// --- When ---
+		err := rng.LoadLocation(tzdata)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrLocation, err)
+		assert.Same(t, loc, rng.Location())
+	})
+}
+
+func Test_Ring_Now(t *testing.T) {
+	t.Run("default location", func(t *testing.T) {
+		// --- Given ---
+		rng := New(WithClock(fixedClock(tzTime)))
+
+		// --- When ---
+		have := rng.Now()
+
+		// --- Then ---
+		assert.Equal(t, tzTime, have)
+		assert.Same(t, time.UTC, have.Location())
+	})
+
+	t.Run("location", func(t *testing.T) {
+		// --- Given ---
+		loc := must.Value(LoadLocation("Europe/Warsaw", tzdata))
+		rng := New(WithClock(fixedClock(tzTime)), WithLocation(loc))
+
+		// --- When ---
+		have := rng.Now()
+
+		// --- Then ---
+		assert.True(t, tzTime.Equal(have))
+		assert.Same(t, loc, have.Location())
+		wStr := "2000-01-02T04:04:05+01:00"
+		assert.Equal(t, wStr, have.Format(time.RFC3339))
+	})
+
+	t.Run("summer time", func(t *testing.T) {
+		// --- Given ---
+		now := time.Date(2000, 7, 1, 12, 0, 0, 0, time.UTC)
+		loc := must.Value(LoadLocation("Europe/Warsaw", tzdata))
+		rng := New(WithClock(fixedClock(now)), WithLocation(loc))
+
+		// --- When ---
+		have := rng.Now()
+
+		// --- Then ---
+		assert.Equal(t, "14:00 CEST", have.Format("15:04 MST"))
+	})
+}
+
+func Test_Ring_In(t *testing.T) {
+	// --- Given ---
+	loc := must.Value(LoadLocation("America/New_York", tzdata))
+	rng := New(WithLocation(loc))
+
+	// --- When ---
+	have := rng.In(tzTime)
+
+	// --- Then ---
+	assert.True(t, tzTime.Equal(have))
+	assert.Same(t, loc, have.Location())
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"errors"
+	"os"
+)
+
+// EnvApplyToProcess makes the environment of the current process (see
+// [os.Environ]) equal to the environment. Process variables not present in
+// the environment are unset. It returns the function restoring the process
+// environment to the state from before the call.
+//
+// The process environment is global state shared by all goroutines, use it
+// only at program edges, for example, before calling a cgo library reading
+// variables with getenv. When the process environment cannot be modified,
+// it is restored, and the error is returned.
+func (env *Env) EnvApplyToProcess() (restore func() error, err error) {
+	prev := EnvSplit(os.Environ())
+	restore = func() error { return setProcessEnv(prev) }
+	if err = setProcessEnv(env.vars()); err != nil {
+		return nil, errors.Join(err, restore())
+	}
+	return restore, nil
+}
+
+// EnvReloadFromProcess replaces all variables in the environment with the
+// variables of the current process (see [os.Environ]). Use it to pick up
+// changes made to the process environment, for example, by a third-party
+// library. For a layered environment, the variables of the parent which are
+// not set in the process are hidden. It panics with an error wrapping
+// [ErrReadOnly] when the environment is frozen.
+func (env *Env) EnvReloadFromProcess() {
+	entries := os.Environ()
+	var parent map[string]string
+	if env.parent != nil {
+		parent = EnvSplit(env.parent.EnvAll())
+	}
+
+	defer env.lock()()
+	env.mustWritable(OpSet, "*")
+	if env.names == nil {
+		env.env = EnvSplit(entries)
+	} else {
+		env.env = make(map[string]string, len(entries))
+		env.names = make(map[string]string, len(entries))
+		env.load(entries)
+	}
+	if env.parent == nil {
+		return
+	}
+	env.hidden = make(map[string]struct{})
+	for key := range parent {
+		if _, exist := env.env[env.name(key)]; !exist {
+			env.hidden[env.fold(key)] = struct{}{}
+		}
+	}
+}
+
+// setProcessEnv makes the process environment equal to the variables by
+// setting changed and unsetting not present ones.
+func setProcessEnv(vars map[string]string) error {
+	var errs []error
+	for key := range EnvSplit(os.Environ()) {
+		if _, exist := vars[key]; !exist {
+			errs = append(errs, os.Unsetenv(key))
+		}
+	}
+	for key, val := range vars {
+		if cur, exist := os.LookupEnv(key); !exist || cur != val {
+			errs = append(errs, os.Setenv(key, val))
+		}
+	}
+	return errors.Join(errs...)
+}
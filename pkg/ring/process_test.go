@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+// keepProcessEnv restores the process environment when the test ends.
+func keepProcessEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("RING_TEST_KEEP", "") // Mark test as modifying environment.
+	prev := EnvSplit(os.Environ())
+	t.Cleanup(func() { _ = setProcessEnv(prev) })
+}
+
+// ringTestEnv returns variables with names starting with "RING_TEST_".
+func ringTestEnv(all []string) map[string]string {
+	ret := make(map[string]string)
+	for key, val := range EnvSplit(all) {
+		if strings.HasPrefix(key, "RING_TEST_") {
+			ret[key] = val
+		}
+	}
+	return ret
+}
+
+func Test_Env_EnvApplyToProcess(t *testing.T) {
+	t.Run("apply and restore", func(t *testing.T) {
+		// --- Given ---
+		keepProcessEnv(t)
+		t.Setenv("RING_TEST_A", "a")
+		t.Setenv("RING_TEST_B", "b")
+		prev := EnvSplit(os.Environ())
+		env := NewEnv([]string{"RING_TEST_A=-a", "RING_TEST_C=c"})
+
+		// --- When ---
+		restore, err := env.EnvApplyToProcess()
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, EnvSplit(env.EnvAll()), EnvSplit(os.Environ()))
+
+		assert.NoError(t, restore())
+		assert.Equal(t, prev, EnvSplit(os.Environ()))
+	})
+
+	t.Run("layered", func(t *testing.T) {
+		// --- Given ---
+		keepProcessEnv(t)
+		t.Setenv("RING_TEST_B", "b")
+		env := NewEnvLayer(NewEnv(os.Environ()))
+		env.EnvSet("RING_TEST_A", "a")
+		env.EnvUnset("RING_TEST_B")
+
+		// --- When ---
+		restore, err := env.EnvApplyToProcess()
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := map[string]string{"RING_TEST_A": "a", "RING_TEST_KEEP": ""}
+		assert.Equal(t, want, ringTestEnv(os.Environ()))
+		assert.NoError(t, restore())
+	})
+
+	t.Run("error - invalid key", func(t *testing.T) {
+		// --- Given ---
+		keepProcessEnv(t)
+		t.Setenv("RING_TEST_A", "a")
+		prev := EnvSplit(os.Environ())
+		env := NewEnv([]string{"RING_TEST_B=b"})
+		env.EnvSet("RING=TEST", "x")
+
+		// --- When ---
+		restore, err := env.EnvApplyToProcess()
+
+		// --- Then ---
+		assert.Error(t, err)
+		assert.Nil(t, restore)
+		assert.Equal(t, prev, EnvSplit(os.Environ()))
+	})
+}
+
+func Test_Env_EnvReloadFromProcess(t *testing.T) {
+	t.Run("not layered", func(t *testing.T) {
+		// --- Given ---
+		keepProcessEnv(t)
+		t.Setenv("RING_TEST_A", "a")
+		env := NewEnv([]string{"RING_TEST_A=-a", "RING_TEST_B=b"})
+
+		// --- When ---
+		env.EnvReloadFromProcess()
+
+		// --- Then ---
+		assert.Equal(t, EnvSplit(os.Environ()), EnvSplit(env.EnvAll()))
+		assert.Equal(t, "a", env.EnvGet("RING_TEST_A"))
+		_, exist := env.EnvLookup("RING_TEST_B")
+		assert.False(t, exist)
+	})
+
+	t.Run("layered", func(t *testing.T) {
+		// --- Given ---
+		keepProcessEnv(t)
+		t.Setenv("RING_TEST_A", "a")
+		parent := NewEnv([]string{"RING_TEST_A=-a", "RING_TEST_B=b"})
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		env.EnvReloadFromProcess()
+
+		// --- Then ---
+		assert.Equal(t, EnvSplit(os.Environ()), EnvSplit(env.EnvAll()))
+		_, exist := env.EnvLookup("RING_TEST_B")
+		assert.False(t, exist)
+		_, unset := env.EnvLayer()
+		assert.Equal(t, []string{"RING_TEST_B"}, unset)
+		assert.Equal(t, "-a", parent.EnvGet("RING_TEST_A"))
+	})
+
+	t.Run("case-insensitive layered", func(t *testing.T) {
+		// --- Given ---
+		keepProcessEnv(t)
+		t.Setenv("RING_TEST_A", "a")
+		parent := NewEnv(
+			[]string{"Ring_Test_A=-a", "Ring_Test_B=b"},
+			WithCaseInsensitive(),
+		)
+		env := NewEnvLayer(parent)
+
+		// --- When ---
+		env.EnvReloadFromProcess()
+
+		// --- Then ---
+		assert.Equal(t, "a", env.EnvGet("ring_test_a"))
+		_, exist := env.EnvLookup("RING_TEST_B")
+		assert.False(t, exist)
+		_, unset := env.EnvLayer()
+		assert.Equal(t, []string{"RING_TEST_B"}, unset)
+		want := EnvSplit(os.Environ())
+		delete(want, "RING_TEST_A")
+		want["Ring_Test_A"] = "a"
+		assert.Equal(t, want, EnvSplit(env.EnvAll()))
+	})
+
+	t.Run("frozen", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+		env.EnvFreeze()
+
+		// --- When ---
+		msg := assert.PanicMsg(t, "read-only environment: set *", func() {
+			env.EnvReloadFromProcess()
+		})
+
+		// --- Then ---
+		assert.NotNil(t, msg)
+		assert.Equal(t, []string{"A=1"}, env.EnvAll())
+	})
+}
+
+func Test_setProcessEnv(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		keepProcessEnv(t)
+		t.Setenv("RING_TEST_A", "a")
+		t.Setenv("RING_TEST_B", "b")
+
+		env := EnvSplit(os.Environ())
+		env["RING_TEST_A"] = "-a"
+		env["RING_TEST_C"] = "c"
+		delete(env, "RING_TEST_B")
+
+		// --- When ---
+		err := setProcessEnv(env)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, env, EnvSplit(os.Environ()))
+		want := map[string]string{
+			"RING_TEST_A":    "-a",
+			"RING_TEST_C":    "c",
+			"RING_TEST_KEEP": "",
+		}
+		assert.Equal(t, want, ringTestEnv(os.Environ()))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		// --- Given ---
+		keepProcessEnv(t)
+
+		// --- When ---
+		err := setProcessEnv(map[string]string{"A=B": "c"})
+
+		// --- Then ---
+		assert.Error(t, err)
+	})
+}
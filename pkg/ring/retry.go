@@ -0,0 +1,265 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Backoff returns the delay before the next attempt after the given number
+// of failed attempts, which is always greater than zero.
+type Backoff func(failed int) time.Duration
+
+// ConstantBackoff returns a [Backoff] with the same delay for all attempts.
+// A delay less than 1ns is treated as 1ns.
+func ConstantBackoff(delay time.Duration) Backoff {
+	delay = max(delay, time.Nanosecond)
+	return func(int) time.Duration { return delay }
+}
+
+// ExponentialBackoff returns a [Backoff] starting with the base delay and
+// doubling it after each failed attempt, up to maxDelay. The zero maxDelay
+// means no limit. A base delay less than 1ns is treated as 1ns.
+func ExponentialBackoff(base, maxDelay time.Duration) Backoff {
+	base = max(base, time.Nanosecond)
+	return func(failed int) time.Duration {
+		delay := base
+		for i := 1; i < failed; i++ {
+			if maxDelay > 0 && delay >= maxDelay {
+				break
+			}
+			if delay > math.MaxInt64/2 {
+				delay = math.MaxInt64
+				break
+			}
+			delay *= 2
+		}
+		if maxDelay > 0 && delay > maxDelay {
+			delay = maxDelay
+		}
+		return delay
+	}
+}
+
+// JitterBackoff returns a [Backoff] randomizing delays of the given one by
+// up to the factor in both directions, for example, the factor 0.2 changes a
+// 1s delay to a random delay between 0.8s and 1.2s. The rnd function returns
+// random numbers in the half-open interval [0.0, 1.0), when nil,
+// [rand.Float64] is used. Randomized delays are kept between 1ns and the
+// maximum [time.Duration].
+func JitterBackoff(b Backoff, factor float64, rnd func() float64) Backoff {
+	if rnd == nil {
+		rnd = rand.Float64
+	}
+	return func(failed int) time.Duration {
+		delay := float64(b(failed))
+		delay += delay * factor * (2*rnd() - 1)
+		if delay >= math.MaxInt64 {
+			return math.MaxInt64
+		}
+		return max(time.Duration(delay), time.Nanosecond)
+	}
+}
+
+// RetryOption configures [Retry].
+type RetryOption func(*retryConfig)
+
+// retryConfig represents [Retry] configuration.
+type retryConfig struct {
+	backoff     Backoff          // Delays between attempts.
+	maxAttempts int              // Maximum attempts, zero for no limit.
+	maxElapsed  time.Duration    // Maximum elapsed time, zero for no limit.
+	retryIf     func(error) bool // Decides if an error is retryable.
+}
+
+// WithRetryBackoff configures delays between attempts. By default,
+// [ExponentialBackoff] starting at 100ms with a 10s limit is used.
+func WithRetryBackoff(b Backoff) RetryOption {
+	return func(cfg *retryConfig) { cfg.backoff = b }
+}
+
+// WithRetryMaxAttempts configures the maximum number of attempts. By
+// default, there are at most 3 attempts. The zero means no limit.
+func WithRetryMaxAttempts(n int) RetryOption {
+	return func(cfg *retryConfig) { cfg.maxAttempts = n }
+}
+
+// WithRetryMaxElapsed configures the maximum time spent retrying, measured
+// from the first attempt. No attempt is made if the delay before it would
+// exceed the limit. By default, there is no limit.
+func WithRetryMaxElapsed(d time.Duration) RetryOption {
+	return func(cfg *retryConfig) { cfg.maxElapsed = d }
+}
+
+// WithRetryIf configures the function deciding if the attempt failing with
+// the given error should be retried. By default, all errors are retried.
+func WithRetryIf(fn func(err error) bool) RetryOption {
+	return func(cfg *retryConfig) { cfg.retryIf = fn }
+}
+
+// Retry calls fn until it succeeds, returns an error which should not be
+// retried, the attempt or elapsed time limit is reached, or the context is
+// done. Delays between attempts are waited for using the [Timekeeper], so
+// retries can be driven by a fake clock in tests.
+//
+// When the limits are reached, the returned error wraps [ErrRetry] and the
+// last error returned by fn. When the context is done while waiting, the
+// returned error wraps the context cause and the last error returned by fn.
+// Errors which should not be retried are returned as is.
+func Retry(
+	ctx context.Context,
+	tk Timekeeper,
+	fn func(ctx context.Context) error,
+	opts ...RetryOption,
+) error {
+	cfg := &retryConfig{
+		backoff:     ExponentialBackoff(100*time.Millisecond, 10*time.Second),
+		maxAttempts: 3,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	start := tk.Now()
+	for failed := 0; ; {
+		if err := context.Cause(ctx); err != nil {
+			return err
+		}
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if cfg.retryIf != nil && !cfg.retryIf(err) {
+			return err
+		}
+		failed++
+		if cfg.maxAttempts > 0 && failed >= cfg.maxAttempts {
+			return fmt.Errorf("%w: %d attempts: %w", ErrRetry, failed, err)
+		}
+		delay := cfg.backoff(failed)
+		if cfg.maxElapsed > 0 && tk.Since(start)+delay > cfg.maxElapsed {
+			return fmt.Errorf("%w: %d attempts: %w", ErrRetry, failed, err)
+		}
+		if serr := SleepContext(ctx, tk, delay); serr != nil {
+			return fmt.Errorf("%w: %w", serr, err)
+		}
+	}
+}
+
+// Retry calls [Retry] with the [Ring] clock.
+func (rng *Ring) Retry(
+	ctx context.Context,
+	fn func(ctx context.Context) error,
+	opts ...RetryOption,
+) error {
+	return Retry(ctx, rng.clock, fn, opts...)
+}
+
+// SleepContext pauses the current goroutine for at least the duration d
+// using the [Timekeeper], or until the context is done, in which case the
+// context cause is returned, see [context.Cause].
+func SleepContext(ctx context.Context, tk Timekeeper, d time.Duration) error {
+	if err := context.Cause(ctx); err != nil {
+		return err
+	}
+	if d <= 0 {
+		return nil
+	}
+	tmr := tk.NewTimer(d)
+	select {
+	case <-tmr.C():
+		return nil
+	case <-ctx.Done():
+		tmr.Stop()
+		return context.Cause(ctx)
+	}
+}
+
+// SleepContext calls [SleepContext] with the [Ring] clock.
+func (rng *Ring) SleepContext(ctx context.Context, d time.Duration) error {
+	return SleepContext(ctx, rng.clock, d)
+}
+
+// TimeoutContext returns a copy of the parent context which is canceled when
+// the duration d passes on the [Timekeeper], so deadlines can be driven by a
+// fake clock in tests. When the context times out both its error and the
+// cause returned by [context.Cause] are [context.DeadlineExceeded]. Calling
+// the returned cancel function releases resources associated with the
+// context.
+//
+// Unlike [context.WithTimeout], the returned context has no deadline, see
+// [context.Context.Deadline], since the time of the [Timekeeper] may differ
+// from the wall clock.
+func TimeoutContext(
+	parent context.Context,
+	tk Timekeeper,
+	d time.Duration,
+) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	tc := newTimeoutCtx(ctx)
+	expire := func() {
+		cancel(context.DeadlineExceeded)
+		tc.close()
+	}
+	if d <= 0 {
+		expire()
+		return tc, func() {}
+	}
+	tmr := tk.AfterFunc(d, expire)
+	return tc, func() {
+		tmr.Stop()
+		cancel(context.Canceled)
+		tc.close()
+	}
+}
+
+// timeoutCtx is a context returned by [TimeoutContext].
+//
+// It has its own done channel, so contexts derived from it do not attach to
+// the wrapped context directly, and take their error from
+// [timeoutCtx.Err] when it is done.
+type timeoutCtx struct {
+	context.Context               // Context canceled on timeout.
+	done            chan struct{} // Closed when the wrapped context is done.
+	close           func()        // Closes the done channel once.
+}
+
+// newTimeoutCtx returns a new [timeoutCtx] wrapping the context.
+func newTimeoutCtx(ctx context.Context) *timeoutCtx {
+	tc := &timeoutCtx{Context: ctx, done: make(chan struct{})}
+	tc.close = sync.OnceFunc(func() { close(tc.done) })
+	context.AfterFunc(ctx, tc.close)
+	return tc
+}
+
+// Done returns a channel which is closed when the context is done.
+func (ctx *timeoutCtx) Done() <-chan struct{} { return ctx.done }
+
+// Err returns nil if the context is not done yet, [context.DeadlineExceeded]
+// when the context timed out, otherwise it returns the error of the wrapped
+// context.
+func (ctx *timeoutCtx) Err() error {
+	select {
+	case <-ctx.done:
+	default:
+		return nil
+	}
+	if context.Cause(ctx.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return ctx.Context.Err()
+}
+
+// TimeoutContext calls [TimeoutContext] with the [Ring] clock.
+func (rng *Ring) TimeoutContext(
+	parent context.Context,
+	d time.Duration,
+) (context.Context, context.CancelFunc) {
+	return TimeoutContext(parent, rng.clock, d)
+}
@@ -0,0 +1,632 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+// tkSleep is a [Timekeeper] recording waits. Its timers fire immediately,
+// moving the time forward, unless it is blocked. Functions passed to
+// AfterFunc are recorded and never called.
+type tkSleep struct {
+	Clock
+	now    time.Time       // Current time.
+	sleeps []time.Duration // Recorded timer durations.
+	funcs  []func()        // Recorded AfterFunc functions.
+	block  bool            // When true, timers never fire.
+}
+
+func (tk *tkSleep) Now() time.Time { return tk.now }
+
+func (tk *tkSleep) Since(t time.Time) time.Duration { return tk.now.Sub(t) }
+
+func (tk *tkSleep) NewTimer(d time.Duration) Timer {
+	tk.sleeps = append(tk.sleeps, d)
+	tmr := &stubTimer{ch: make(chan time.Time, 1)}
+	if !tk.block {
+		tk.now = tk.now.Add(d)
+		tmr.ch <- tk.now
+	}
+	return tmr
+}
+
+func (tk *tkSleep) AfterFunc(d time.Duration, f func()) Timer {
+	tk.sleeps = append(tk.sleeps, d)
+	tk.funcs = append(tk.funcs, f)
+	return &stubTimer{}
+}
+
+// stubTimer is a [Timer] with the given channel.
+type stubTimer struct{ ch chan time.Time }
+
+func (tmr *stubTimer) C() <-chan time.Time { return tmr.ch }
+
+func (tmr *stubTimer) Stop() bool { return true }
+
+func (tmr *stubTimer) Reset(time.Duration) bool { return true }
+
+// failN returns a function failing with the error n times and counting
+// calls.
+func failN(n int, err error, calls *int) func(context.Context) error {
+	return func(context.Context) error {
+		*calls++
+		if *calls <= n {
+			return err
+		}
+		return nil
+	}
+}
+
+func Test_ConstantBackoff(t *testing.T) {
+	t.Run("delay", func(t *testing.T) {
+		// --- Given ---
+		b := ConstantBackoff(time.Second)
+
+		// --- When ---
+		have := []time.Duration{b(1), b(2), b(10)}
+
+		// --- Then ---
+		want := []time.Duration{time.Second, time.Second, time.Second}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("zero delay", func(t *testing.T) {
+		// --- Given ---
+		b := ConstantBackoff(0)
+
+		// --- When ---
+		have := b(1)
+
+		// --- Then ---
+		assert.Equal(t, time.Nanosecond, have)
+	})
+}
+
+func Test_ExponentialBackoff_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		base   time.Duration
+		max    time.Duration
+		failed int
+		want   time.Duration
+	}{
+		{"first", time.Second, 0, 1, time.Second},
+		{"second", time.Second, 0, 2, 2 * time.Second},
+		{"fifth", time.Second, 0, 5, 16 * time.Second},
+		{"below max", time.Second, 5 * time.Second, 3, 4 * time.Second},
+		{"at max", time.Second, 4 * time.Second, 3, 4 * time.Second},
+		{"above max", time.Second, 5 * time.Second, 4, 5 * time.Second},
+		{"base above max", time.Minute, time.Second, 1, time.Second},
+		{"large attempt", time.Second, time.Minute, 1000, time.Minute},
+		{"overflow", time.Second, 0, 1000, math.MaxInt64},
+		{"zero base", 0, 0, 1, time.Nanosecond},
+		{"zero base second", 0, 0, 2, 2 * time.Nanosecond},
+		{"negative base", -time.Second, 0, 1, time.Nanosecond},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- Given ---
+			b := ExponentialBackoff(tc.base, tc.max)
+
+			// --- When ---
+			have := b(tc.failed)
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_JitterBackoff(t *testing.T) {
+	t.Run("bounds", func(t *testing.T) {
+		// --- Given ---
+		b := ConstantBackoff(time.Second)
+
+		// --- When ---
+		low := JitterBackoff(b, 0.2, func() float64 { return 0 })(1)
+		mid := JitterBackoff(b, 0.2, func() float64 { return 0.5 })(1)
+		high := JitterBackoff(b, 0.2, func() float64 { return 0.75 })(1)
+
+		// --- Then ---
+		assert.Equal(t, 800*time.Millisecond, low)
+		assert.Equal(t, time.Second, mid)
+		assert.Equal(t, 1100*time.Millisecond, high)
+	})
+
+	t.Run("greater than zero", func(t *testing.T) {
+		// --- Given ---
+		b := ConstantBackoff(time.Second)
+
+		// --- When ---
+		have := JitterBackoff(b, 2, func() float64 { return 0 })(1)
+
+		// --- Then ---
+		assert.Equal(t, time.Nanosecond, have)
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		// --- Given ---
+		b := ExponentialBackoff(time.Second, 0)
+
+		// --- When ---
+		have := JitterBackoff(b, 0.5, func() float64 { return 0.99 })(80)
+
+		// --- Then ---
+		assert.Equal(t, time.Duration(math.MaxInt64), have)
+	})
+
+	t.Run("maximum without jitter", func(t *testing.T) {
+		// --- Given ---
+		b := ConstantBackoff(math.MaxInt64)
+
+		// --- When ---
+		have := JitterBackoff(b, 0.5, func() float64 { return 0.5 })(1)
+
+		// --- Then ---
+		assert.Equal(t, time.Duration(math.MaxInt64), have)
+	})
+
+	t.Run("default random source", func(t *testing.T) {
+		// --- Given ---
+		b := JitterBackoff(ConstantBackoff(time.Second), 0.5, nil)
+
+		for range 100 {
+			// --- When ---
+			have := b(1)
+
+			// --- Then ---
+			assert.True(t, have >= 500*time.Millisecond)
+			assert.True(t, have < 1500*time.Millisecond)
+		}
+	})
+}
+
+func Test_Retry(t *testing.T) {
+	t.Run("success on first attempt", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		var calls int
+		fn := failN(0, errors.New("e"), &calls)
+
+		// --- When ---
+		err := Retry(context.Background(), tk, fn)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls)
+		assert.Nil(t, tk.sleeps)
+	})
+
+	t.Run("success after retries", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		var calls int
+		fn := failN(2, errors.New("e"), &calls)
+
+		// --- When ---
+		err := Retry(context.Background(), tk, fn)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}
+		assert.Equal(t, want, tk.sleeps)
+	})
+
+	t.Run("error - max attempts by default", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		var calls int
+		e := errors.New("e")
+		fn := failN(10, e, &calls)
+
+		// --- When ---
+		err := Retry(context.Background(), tk, fn)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrRetry, err)
+		assert.ErrorIs(t, e, err)
+		assert.ErrorEqual(t, "retry limit reached: 3 attempts: e", err)
+		assert.Equal(t, 3, calls)
+		assert.Len(t, 2, tk.sleeps)
+	})
+
+	t.Run("error - max attempts", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		var calls int
+		fn := failN(10, errors.New("e"), &calls)
+
+		// --- When ---
+		err := Retry(
+			context.Background(),
+			tk,
+			fn,
+			WithRetryMaxAttempts(5),
+			WithRetryBackoff(ConstantBackoff(time.Second)),
+		)
+
+		// --- Then ---
+		assert.ErrorEqual(t, "retry limit reached: 5 attempts: e", err)
+		assert.Equal(t, 5, calls)
+		assert.Equal(t, tzTime.Add(4*time.Second), tk.now)
+	})
+
+	t.Run("no attempt limit", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		var calls int
+		fn := failN(20, errors.New("e"), &calls)
+
+		// --- When ---
+		err := Retry(context.Background(), tk, fn, WithRetryMaxAttempts(0))
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, 21, calls)
+	})
+
+	t.Run("error - max elapsed", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		var calls int
+		fn := failN(10, errors.New("e"), &calls)
+
+		// --- When ---
+		err := Retry(
+			context.Background(),
+			tk,
+			fn,
+			WithRetryMaxAttempts(0),
+			WithRetryMaxElapsed(10*time.Second),
+			WithRetryBackoff(ConstantBackoff(3*time.Second)),
+		)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrRetry, err)
+		assert.ErrorEqual(t, "retry limit reached: 4 attempts: e", err)
+		assert.Equal(t, 4, calls)
+		assert.Equal(t, tzTime.Add(9*time.Second), tk.now)
+	})
+
+	t.Run("error - not retryable", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		var calls int
+		e := errors.New("e")
+		fn := failN(10, e, &calls)
+		retryIf := func(err error) bool { return !errors.Is(err, e) }
+
+		// --- When ---
+		err := Retry(context.Background(), tk, fn, WithRetryIf(retryIf))
+
+		// --- Then ---
+		assert.Same(t, e, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("error - context done before first attempt", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var calls int
+		fn := failN(10, errors.New("e"), &calls)
+
+		// --- When ---
+		err := Retry(ctx, tk, fn)
+
+		// --- Then ---
+		assert.ErrorIs(t, context.Canceled, err)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("error - context done by attempt", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls int
+		e := errors.New("e")
+		fn := func(context.Context) error {
+			calls++
+			cancel()
+			return e
+		}
+
+		// --- When ---
+		err := Retry(ctx, tk, fn)
+
+		// --- Then ---
+		assert.ErrorIs(t, context.Canceled, err)
+		assert.ErrorIs(t, e, err)
+		assert.ErrorEqual(t, "context canceled: e", err)
+		assert.Equal(t, 1, calls)
+		assert.Nil(t, tk.sleeps)
+	})
+
+	t.Run("error - context done while waiting", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime, block: true}
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls int
+		e := errors.New("e")
+		fn := func(context.Context) error {
+			calls++
+			go cancel()
+			return e
+		}
+
+		// --- When ---
+		err := Retry(ctx, tk, fn)
+
+		// --- Then ---
+		assert.ErrorIs(t, context.Canceled, err)
+		assert.ErrorIs(t, e, err)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond}, tk.sleeps)
+	})
+
+	t.Run("context passed to function", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		type key struct{}
+		ctx := context.WithValue(context.Background(), key{}, "v")
+		var have any
+		fn := func(ctx context.Context) error {
+			have = ctx.Value(key{})
+			return nil
+		}
+
+		// --- When ---
+		err := Retry(ctx, tk, fn)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "v", have)
+	})
+}
+
+func Test_Ring_Retry(t *testing.T) {
+	// --- Given ---
+	tk := &tkSleep{now: tzTime}
+	rng := New(WithTimekeeper(tk))
+	var calls int
+	fn := failN(1, errors.New("e"), &calls)
+	opt := WithRetryBackoff(ConstantBackoff(time.Second))
+
+	// --- When ---
+	err := rng.Retry(context.Background(), fn, opt)
+
+	// --- Then ---
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []time.Duration{time.Second}, tk.sleeps)
+}
+
+func Test_SleepContext(t *testing.T) {
+	t.Run("sleep", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+
+		// --- When ---
+		err := SleepContext(context.Background(), tk, time.Second)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Second}, tk.sleeps)
+		assert.Equal(t, tzTime.Add(time.Second), tk.now)
+	})
+
+	t.Run("zero duration", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+
+		// --- When ---
+		err := SleepContext(context.Background(), tk, 0)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Nil(t, tk.sleeps)
+	})
+
+	t.Run("error - context done", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// --- When ---
+		err := SleepContext(ctx, tk, time.Second)
+
+		// --- Then ---
+		assert.ErrorIs(t, context.Canceled, err)
+		assert.Nil(t, tk.sleeps)
+	})
+
+	t.Run("error - context done while sleeping", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime, block: true}
+		e := errors.New("e")
+		ctx, cancel := context.WithCancelCause(context.Background())
+		go cancel(e)
+
+		// --- When ---
+		err := SleepContext(ctx, tk, time.Hour)
+
+		// --- Then ---
+		assert.Same(t, e, err)
+		assert.Equal(t, []time.Duration{time.Hour}, tk.sleeps)
+	})
+
+	t.Run("real clock", func(t *testing.T) {
+		// --- Given ---
+		clk := Clock(NowUTC)
+		start := time.Now()
+
+		// --- When ---
+		err := SleepContext(context.Background(), clk, time.Millisecond)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.True(t, time.Since(start) >= time.Millisecond)
+	})
+}
+
+func Test_Ring_SleepContext(t *testing.T) {
+	// --- Given ---
+	tk := &tkSleep{now: tzTime}
+	rng := New(WithTimekeeper(tk))
+
+	// --- When ---
+	err := rng.SleepContext(context.Background(), time.Second)
+
+	// --- Then ---
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second}, tk.sleeps)
+}
+
+func Test_TimeoutContext(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		ctx, cancel := TimeoutContext(context.Background(), tk, time.Second)
+		defer cancel()
+
+		// --- When ---
+		tk.funcs[0]()
+
+		// --- Then ---
+		<-ctx.Done()
+		assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+		assert.Equal(t, context.DeadlineExceeded, context.Cause(ctx))
+		assert.Equal(t, []time.Duration{time.Second}, tk.sleeps)
+		_, ok := ctx.Deadline()
+		assert.False(t, ok)
+	})
+
+	t.Run("derived context timeout", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		ctx, cancel := TimeoutContext(context.Background(), tk, time.Second)
+		defer cancel()
+		child, cCancel := context.WithCancel(ctx)
+		defer cCancel()
+
+		// --- When ---
+		tk.funcs[0]()
+
+		// --- Then ---
+		<-child.Done()
+		assert.Equal(t, context.DeadlineExceeded, child.Err())
+		assert.Equal(t, context.DeadlineExceeded, context.Cause(child))
+	})
+
+	t.Run("derived context cancel", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		ctx, cancel := TimeoutContext(context.Background(), tk, time.Second)
+		child, cCancel := context.WithCancel(ctx)
+		defer cCancel()
+
+		// --- When ---
+		cancel()
+
+		// --- Then ---
+		<-child.Done()
+		assert.Equal(t, context.Canceled, child.Err())
+	})
+
+	t.Run("not done", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+
+		// --- When ---
+		ctx, cancel := TimeoutContext(context.Background(), tk, time.Second)
+		defer cancel()
+
+		// --- Then ---
+		assert.NoError(t, ctx.Err())
+		assert.NoError(t, context.Cause(ctx))
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		ctx, cancel := TimeoutContext(context.Background(), tk, time.Second)
+
+		// --- When ---
+		cancel()
+
+		// --- Then ---
+		assert.Equal(t, context.Canceled, ctx.Err())
+		assert.Same(t, context.Canceled, context.Cause(ctx))
+	})
+
+	t.Run("parent canceled", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+		parent, pCancel := context.WithCancel(context.Background())
+		ctx, cancel := TimeoutContext(parent, tk, time.Second)
+		defer cancel()
+
+		// --- When ---
+		pCancel()
+
+		// --- Then ---
+		<-ctx.Done()
+		assert.Equal(t, context.Canceled, ctx.Err())
+		assert.Same(t, context.Canceled, context.Cause(ctx))
+	})
+
+	t.Run("non-positive duration", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime}
+
+		// --- When ---
+		ctx, cancel := TimeoutContext(context.Background(), tk, 0)
+		defer cancel()
+
+		// --- Then ---
+		assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+		assert.Equal(t, context.DeadlineExceeded, context.Cause(ctx))
+		assert.Nil(t, tk.funcs)
+	})
+
+	t.Run("sleep aborted", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkSleep{now: tzTime, block: true}
+		ctx, cancel := TimeoutContext(context.Background(), tk, time.Second)
+		defer cancel()
+		go tk.funcs[0]()
+
+		// --- When ---
+		err := SleepContext(ctx, tk, time.Hour)
+
+		// --- Then ---
+		assert.ErrorIs(t, context.DeadlineExceeded, err)
+	})
+}
+
+func Test_Ring_TimeoutContext(t *testing.T) {
+	// --- Given ---
+	tk := &tkSleep{now: tzTime}
+	rng := New(WithTimekeeper(tk))
+
+	// --- When ---
+	ctx, cancel := rng.TimeoutContext(context.Background(), time.Minute)
+	defer cancel()
+
+	// --- Then ---
+	assert.NoError(t, ctx.Err())
+	assert.Equal(t, []time.Duration{time.Minute}, tk.sleeps)
+}
@@ -5,9 +5,13 @@ package ring
 
 import (
 	"errors"
+	"fmt"
 	"io/fs"
+	"log/slog"
+	"maps"
 	"os"
 	"slices"
+	"sync"
 	"time"
 )
 
@@ -22,17 +26,78 @@ var (
 
 	// ErrNoFsAccess is returned when [Ring] has no filesystem access.
 	ErrNoFsAccess = errors.New("no filesystem access")
+
+	// ErrReqEnv indicates a required environment variable is not set.
+	ErrReqEnv = errors.New("required environment variable")
+
+	// ErrInvEnv indicates an environment variable has a value which cannot be
+	// parsed as the requested type.
+	ErrInvEnv = errors.New("invalid environment variable")
+
+	// ErrInvTarget is returned when a decoding target is not a non-nil
+	// pointer to a struct.
+	ErrInvTarget = errors.New("invalid decode target")
+
+	// ErrDotenv indicates dotenv data has invalid syntax.
+	ErrDotenv = errors.New("invalid dotenv syntax")
+
+	// ErrBadSubst indicates a malformed variable reference.
+	ErrBadSubst = errors.New("bad substitution")
+
+	// ErrReadOnly indicates an attempt to modify a read-only environment.
+	ErrReadOnly = errors.New("read-only environment")
+
+	// ErrFormat indicates an unknown or unsupported environment format.
+	ErrFormat = errors.New("unsupported environment format")
+
+	// ErrSyntax indicates serialized environment has invalid syntax.
+	ErrSyntax = errors.New("invalid environment syntax")
+
+	// ErrLocation indicates a time zone location which cannot be loaded.
+	ErrLocation = errors.New("unknown time zone")
+
+	// ErrRetry indicates [Retry] reached the attempt or elapsed time limit.
+	ErrRetry = errors.New("retry limit reached")
 )
 
 // Clock defines a function signature that returns the current time in UTC.
+// It implements [Timekeeper], see its methods for details.
 type Clock func() time.Time
 
 // Option configures a [Ring] during creation with [New].
 type Option func(*Ring)
 
-// WithEnv configures a [Ring] with the given environment variables.
-func WithEnv(env []string) Option {
-	return func(rng *Ring) { rng.hidEnv = NewEnv(env) }
+// WithEnv configures a [Ring] with the given environment variables. The
+// options configure the environment, see [NewEnv].
+func WithEnv(env []string, opts ...EnvOption) Option {
+	return func(rng *Ring) { rng.hidEnv = NewEnv(env, opts...) }
+}
+
+// WithEnvLayer configures a [Ring] with an environment layered on top of the
+// parent environment. See [NewEnvLayer] for details.
+func WithEnvLayer(parent Environ) Option {
+	return func(rng *Ring) { rng.hidEnv = NewEnvLayer(parent) }
+}
+
+// WithEnvFrozen configures a [Ring] with a frozen environment layered on top
+// of the parent environment. Variables of the parent are visible in the
+// [Ring], but any attempt to modify the environment through the [Ring]
+// panics. Use it to hand the environment to code which must not modify it.
+// See [Env.EnvFreeze] for details.
+func WithEnvFrozen(parent Environ) Option {
+	return func(rng *Ring) {
+		rng.hidEnv = NewEnvLayer(parent)
+		rng.hidEnv.EnvFreeze()
+	}
+}
+
+// WithConcurrency configures a [Ring] safe for concurrent use by multiple
+// goroutines. The environment (see [Env.EnvConcurrent]), standard I/O
+// streams (see [IO.IOConcurrent]), metadata and location are guarded by
+// mutexes. Every method call is atomic, but sequences of calls are not.
+// Without this option, a [Ring] must not be modified concurrently.
+func WithConcurrency() Option {
+	return func(rng *Ring) { rng.mx = &sync.RWMutex{} }
 }
 
 // WithName configures a [Ring] with the given program name.
@@ -47,10 +112,18 @@ func WithArgs(args []string) Option {
 }
 
 // WithClock configures a [Ring] with a custom [Clock] function for time.
+// Waiting and scheduling methods of [Ring.Timekeeper] are backed by the
+// [time] package, see [Clock.Sleep].
 func WithClock(clk Clock) Option {
 	return func(rng *Ring) { rng.clock = clk }
 }
 
+// WithTimekeeper configures a [Ring] with a custom [Timekeeper] for telling
+// time, waiting and scheduling events.
+func WithTimekeeper(tk Timekeeper) Option {
+	return func(rng *Ring) { rng.clock = tk }
+}
+
 // WithMeta configures a [Ring] with the given metadata.
 func WithMeta(meta map[string]any) Option {
 	return func(rng *Ring) { rng.meta = meta }
@@ -61,24 +134,40 @@ func WithFS(filesystem fs.FS) Option {
 	return func(rng *Ring) { rng.fs = filesystem }
 }
 
+// WithWritableFS configures a [Ring] with access to a writable filesystem.
+// It is independent of the read-only filesystem configured with [WithFS].
+func WithWritableFS(fsys WritableFS) Option {
+	return func(rng *Ring) { rng.wfs = fsys }
+}
+
 // Hide embedded fields.
 type (
 	hidEnv = Env
 	hidIO  = IO
 )
 
-var _ Streamer = Ring{} // Compile time check.
+// Compile time checks.
+var (
+	_ Streamer       = Ring{}
+	_ slog.LogValuer = &Ring{}
+)
 
 // Ring represents a program execution context, encapsulating standard I/O
 // streams, environment variables, arguments, a clock, and metadata.
 type Ring struct {
 	*hidEnv                // Program environment.
 	*hidIO                 // Standard I/O streams.
-	clock   Clock          // Function returning current time in UTC.
+	clock   Timekeeper     // Time source, waiting and scheduling.
+	loc     *time.Location // Location used to present time.
 	fs      fs.FS          // Program filesystem.
+	wfs     WritableFS     // Program writable filesystem.
 	name    string         // Program name.
 	args    []string       // Program arguments (excluding program name).
 	meta    map[string]any // Arbitrary metadata.
+
+	// Guards metadata and location, nil when not concurrent, see
+	// [WithConcurrency].
+	mx *sync.RWMutex
 }
 
 // defaultRing returns a new [Ring] with default configuration.
@@ -86,15 +175,18 @@ type Ring struct {
 // Configuration:
 //   - Standard I/O: [os.Stdin], [os.Stdout], [os.Stderr]
 //   - Clock: [NowUTC]
+//   - Location: [time.UTC]
 //   - Name: os.Args[0] (program name)
 //   - Args: os.Args[1:] (excludes program name)
 //   - Environment: nil
 //   - Metadata: nil
 //   - Filesystem: nil
+//   - Writable filesystem: nil
 func defaultRing() *Ring {
 	return &Ring{
 		hidIO: NewIO(),
-		clock: NowUTC,
+		clock: sysClock,
+		loc:   time.UTC,
 		name:  os.Args[0],
 		args:  os.Args[1:],
 	}
@@ -106,10 +198,12 @@ func defaultRing() *Ring {
 //   - Standard I/O: [os.Stdin], [os.Stdout], [os.Stderr]
 //   - Environment: [os.Environ]
 //   - Clock: [NowUTC]
+//   - Location: [time.UTC]
 //   - Args: os.Args[1:]
 //   - Name: os.Args[0]
 //   - Metadata: empty map
 //   - Filesystem: no access.
+//   - Writable filesystem: no access.
 //
 // Example:
 //
@@ -128,11 +222,28 @@ func New(opts ...Option) *Ring {
 	if rng.meta == nil {
 		rng.meta = make(map[string]any)
 	}
+	if rng.mx != nil {
+		rng.hidEnv.EnvConcurrent()
+		rng.hidIO.IOConcurrent()
+	}
 	return rng
 }
 
-// Clock returns function returning current time in UTC.
-func (rng *Ring) Clock() func() time.Time { return rng.clock }
+// Clock returns function returning current time in UTC. When the [Ring] was
+// configured with [WithClock], the configured function is returned.
+func (rng *Ring) Clock() func() time.Time {
+	switch clk := rng.clock.(type) {
+	case Clock:
+		return clk
+	case *systemClock:
+		return clk.Clock
+	}
+	return rng.clock.Now
+}
+
+// Timekeeper returns the [Timekeeper] for telling time, waiting and
+// scheduling events. Use it instead of the [time] package functions.
+func (rng *Ring) Timekeeper() Timekeeper { return rng.clock }
 
 // Args returns the program arguments, excluding the program name.
 func (rng *Ring) Args() []string { return rng.args }
@@ -146,9 +257,14 @@ func (rng *Ring) SetArgs(args []string) *Ring {
 // Name returns program name.
 func (rng *Ring) Name() string { return rng.name }
 
+// Concurrent returns true if the [Ring] is safe for concurrent use, see
+// [WithConcurrency].
+func (rng *Ring) Concurrent() bool { return rng.mx != nil }
+
 // MetaSet sets the metadata value for the given key. If the key already exists,
 // its value is overwritten. The value may be any type, including nil.
 func (rng *Ring) MetaSet(key string, value any) {
+	defer lockMutex(rng.mx)()
 	rng.meta[key] = value
 }
 
@@ -156,6 +272,7 @@ func (rng *Ring) MetaSet(key string, value any) {
 // key exists, it returns the value, which may be nil or empty. If the key does
 // not exist, it returns nil.
 func (rng *Ring) MetaGet(key string) any {
+	defer rlockMutex(rng.mx)()
 	return rng.meta[key]
 }
 
@@ -163,6 +280,7 @@ func (rng *Ring) MetaGet(key string) any {
 // the key exists in the metadata, it returns the value (which may be nil or
 // empty) and true. If the key does not exist, it returns nil and false.
 func (rng *Ring) MetaLookup(key string) (any, bool) {
+	defer rlockMutex(rng.mx)()
 	val, ok := rng.meta[key]
 	return val, ok
 }
@@ -170,11 +288,20 @@ func (rng *Ring) MetaLookup(key string) (any, bool) {
 // MetaDelete removes the metadata value associated with the given key. If the
 // key does not exist, the method has no effect.
 func (rng *Ring) MetaDelete(key string) {
+	defer lockMutex(rng.mx)()
 	delete(rng.meta, key)
 }
 
-// MetaAll returns metadata map.
-func (rng *Ring) MetaAll() map[string]any { return rng.meta }
+// MetaAll returns metadata map. For a concurrent [Ring] (see
+// [WithConcurrency]), it returns a copy of the metadata map.
+func (rng *Ring) MetaAll() map[string]any {
+	if rng.mx == nil {
+		return rng.meta
+	}
+	rng.mx.RLock()
+	defer rng.mx.RUnlock()
+	return maps.Clone(rng.meta)
+}
 
 // FS returns a hierarchical file system associated with the instance.
 func (rng *Ring) FS() (fs.FS, error) {
@@ -184,17 +311,110 @@ func (rng *Ring) FS() (fs.FS, error) {
 	return rng.fs, nil
 }
 
+// WritableFS returns a writable filesystem associated with the instance. It
+// returns [ErrNoFsAccess] when the [Ring] has no writable filesystem access.
+func (rng *Ring) WritableFS() (WritableFS, error) {
+	if rng.wfs == nil {
+		return nil, ErrNoFsAccess
+	}
+	return rng.wfs, nil
+}
+
+// LoadDotenv loads dotenv files with the given names from the [Ring]
+// filesystem into its environment. It returns [ErrNoFsAccess] when the
+// [Ring] has no filesystem access. See [Env.EnvLoadDotenv] for details.
+func (rng *Ring) LoadDotenv(prec Precedence, names ...string) error {
+	fsys, err := rng.FS()
+	if err != nil {
+		return err
+	}
+	return rng.EnvLoadDotenv(fsys, prec, names...)
+}
+
+// LoadEnvdir loads variables from the directory in the [Ring] filesystem
+// into its environment. It returns [ErrNoFsAccess] when the [Ring] has no
+// filesystem access. See [Env.EnvLoadEnvdir] for details.
+func (rng *Ring) LoadEnvdir(
+	dir string,
+	prec Precedence,
+	opts ...EnvdirOption,
+) error {
+	fsys, err := rng.FS()
+	if err != nil {
+		return err
+	}
+	return rng.EnvLoadEnvdir(fsys, dir, prec, opts...)
+}
+
 // Clone creates a deep copy of the [Ring] instance (except metadata structure).
 //
-// Changes to metadata will be visible in all clones.
+// Changes to metadata will be visible in all clones. Clones of a concurrent
+// [Ring] share the metadata mutex.
 func (rng *Ring) Clone() *Ring {
 	return &Ring{
 		hidEnv: rng.hidEnv.EnvClone(),
 		hidIO:  rng.hidIO.IOClone(),
 		clock:  rng.clock,
+		loc:    rng.Location(),
 		fs:     rng.fs,
+		wfs:    rng.wfs,
 		name:   rng.name,
 		args:   slices.Clone(rng.args),
 		meta:   rng.meta,
+		mx:     rng.mx,
 	}
 }
+
+// Derive creates a copy of the [Ring] instance (except metadata structure)
+// whose environment is a layer on top of the original environment (see
+// [NewEnvLayer]). It is cheaper than [Ring.Clone] because the environment is
+// not copied. Environment changes made by the derived [Ring] are not visible
+// in the original, while changes made to the original environment are
+// visible in the derived [Ring] unless it sets or unsets the same variables.
+//
+// Changes to metadata will be visible in all derived instances.
+func (rng *Ring) Derive() *Ring {
+	cpy := &Ring{
+		hidEnv: NewEnvLayer(rng.hidEnv),
+		hidIO:  rng.hidIO.IOClone(),
+		clock:  rng.clock,
+		loc:    rng.Location(),
+		fs:     rng.fs,
+		wfs:    rng.wfs,
+		name:   rng.name,
+		args:   slices.Clone(rng.args),
+		meta:   rng.meta,
+		mx:     rng.mx,
+	}
+	if rng.mx != nil {
+		cpy.hidEnv.EnvConcurrent()
+	}
+	return cpy
+}
+
+// String returns a [Ring] dump with the program name, arguments and the
+// redacted environment (see [Env.EnvRedacted]).
+func (rng *Ring) String() string {
+	return fmt.Sprintf("name=%s args=%q env=%s", rng.name, rng.args, rng.hidEnv)
+}
+
+// GoString returns a [Ring] dump in Go syntax with the redacted environment
+// (see [Env.EnvRedacted]).
+func (rng *Ring) GoString() string {
+	return fmt.Sprintf(
+		"&ring.Ring{name:%q, args:%#v, env:%#v}",
+		rng.name,
+		rng.args,
+		rng.hidEnv,
+	)
+}
+
+// LogValue implements [slog.LogValuer] returning the program name, arguments
+// and the redacted environment (see [Env.EnvRedacted]) as a group.
+func (rng *Ring) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("name", rng.name),
+		slog.Any("args", rng.args),
+		slog.Any("env", rng.hidEnv),
+	)
+}
@@ -4,8 +4,12 @@
 package ring
 
 import (
+	"bytes"
+	"fmt"
+	"log/slog"
 	"os"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/ctx42/testing/pkg/assert"
@@ -21,6 +25,88 @@ func Test_WithEnv(t *testing.T) {
 
 	// --- Then ---
 	assert.Equal(t, map[string]string{"A": "1", "B": "2"}, rng.hidEnv.env)
+	assert.False(t, rng.EnvCaseInsensitive())
+}
+
+func Test_WithEnv_options(t *testing.T) {
+	// --- When ---
+	rng := New(WithEnv([]string{"Path=a"}, WithCaseInsensitive()))
+
+	// --- Then ---
+	assert.True(t, rng.EnvCaseInsensitive())
+	assert.Equal(t, "a", rng.EnvGet("PATH"))
+	assert.True(t, rng.Derive().EnvCaseInsensitive())
+}
+
+func Test_WithEnvLayer(t *testing.T) {
+	// --- Given ---
+	rng := &Ring{}
+	parent := NewEnv([]string{"A=1"})
+
+	// --- When ---
+	WithEnvLayer(parent)(rng)
+
+	// --- Then ---
+	assert.Same(t, parent, rng.hidEnv.parent)
+	assert.Equal(t, []string{"A=1"}, rng.EnvAll())
+}
+
+func Test_WithEnvFrozen(t *testing.T) {
+	// --- Given ---
+	rng := &Ring{}
+	parent := NewEnv([]string{"A=1"})
+
+	// --- When ---
+	WithEnvFrozen(parent)(rng)
+
+	// --- Then ---
+	assert.Same(t, parent, rng.hidEnv.parent)
+	assert.True(t, rng.EnvFrozen())
+	assert.Equal(t, []string{"A=1"}, rng.EnvAll())
+	assert.Panic(t, func() { rng.EnvSet("B", "2") })
+	assert.Equal(t, []string{"A=1"}, parent.EnvAll())
+
+	rng.EnvParent().EnvSet("A", "hacked")
+	assert.Equal(t, []string{"A=1"}, parent.EnvAll())
+	assert.Equal(t, []string{"A=1"}, rng.EnvAll())
+}
+
+func Test_WithEnvFrozen_clone(t *testing.T) {
+	// --- Given ---
+	parent := NewEnv([]string{"A=1"})
+	rng := New(WithEnvFrozen(parent))
+
+	// --- When ---
+	have := rng.Clone()
+
+	// --- Then ---
+	have.EnvParent().EnvSet("A", "hacked")
+	assert.Equal(t, []string{"A=1"}, parent.EnvAll())
+	assert.Equal(t, []string{"A=1"}, rng.EnvAll())
+	assert.Equal(t, []string{"A=1"}, have.EnvAll())
+}
+
+func Test_WithConcurrency(t *testing.T) {
+	t.Run("option", func(t *testing.T) {
+		// --- Given ---
+		rng := &Ring{}
+
+		// --- When ---
+		WithConcurrency()(rng)
+
+		// --- Then ---
+		assert.NotNil(t, rng.mx)
+	})
+
+	t.Run("new", func(t *testing.T) {
+		// --- When ---
+		have := New(WithConcurrency(), WithEnv([]string{"A=1"}))
+
+		// --- Then ---
+		assert.NotNil(t, have.mx)
+		assert.NotNil(t, have.hidEnv.mx)
+		assert.NotNil(t, have.hidIO.mx)
+	})
 }
 
 func Test_WithName(t *testing.T) {
@@ -64,7 +150,19 @@ func Test_WithClock(t *testing.T) {
 	WithClock(time.Now)(rng)
 
 	// --- Then ---
-	assert.Same(t, time.Now, rng.clock)
+	assert.Same(t, time.Now, rng.Clock())
+}
+
+func Test_WithTimekeeper(t *testing.T) {
+	// --- Given ---
+	rng := &Ring{}
+	tk := &tkNow{}
+
+	// --- When ---
+	WithTimekeeper(tk)(rng)
+
+	// --- Then ---
+	assert.Same(t, tk, rng.clock)
 }
 
 func Test_WithFS(t *testing.T) {
@@ -81,6 +179,19 @@ func Test_WithFS(t *testing.T) {
 	assert.Same(t, FS, rng.fs)
 }
 
+func Test_WithWritableFS(t *testing.T) {
+	// --- Given ---
+	rng := &Ring{}
+	wfs := must.Value(OpenRootFS(t.TempDir()))
+	t.Cleanup(func() { _ = wfs.Close() })
+
+	// --- When ---
+	WithWritableFS(wfs)(rng)
+
+	// --- Then ---
+	assert.Same(t, wfs, rng.wfs)
+}
+
 func Test_defaultRing(t *testing.T) {
 	// --- When ---
 	have := defaultRing()
@@ -90,12 +201,16 @@ func Test_defaultRing(t *testing.T) {
 	assert.Same(t, os.Stdin, have.stdin)
 	assert.Same(t, os.Stdout, have.stdout)
 	assert.Same(t, os.Stderr, have.stderr)
-	assert.Same(t, NowUTC, have.clock)
+	assert.Same(t, sysClock, have.clock)
+	assert.Same(t, NowUTC, have.Clock())
+	assert.Same(t, time.UTC, have.loc)
 	assert.Nil(t, have.fs)
+	assert.Nil(t, have.wfs)
 	assert.Equal(t, os.Args[0], have.name)
 	assert.Equal(t, os.Args[1:], have.args)
 	assert.Nil(t, have.meta)
-	assert.Fields(t, 7, Ring{})
+	assert.Nil(t, have.mx)
+	assert.Fields(t, 10, Ring{})
 }
 
 func Test_New(t *testing.T) {
@@ -108,13 +223,18 @@ func Test_New(t *testing.T) {
 		assert.Same(t, os.Stdin, have.stdin)
 		assert.Same(t, os.Stdout, have.stdout)
 		assert.Same(t, os.Stderr, have.stderr)
-		assert.Same(t, NowUTC, have.clock)
+		assert.Same(t, NowUTC, have.Clock())
+		assert.Same(t, time.UTC, have.loc)
 		assert.Nil(t, have.fs)
+		assert.Nil(t, have.wfs)
 		assert.Equal(t, os.Args[0], have.name)
 		assert.Equal(t, os.Args[1:], have.args)
 		assert.NotNil(t, have.meta)
 		assert.Empty(t, have.meta)
-		assert.Fields(t, 7, Ring{})
+		assert.Nil(t, have.mx)
+		assert.Nil(t, have.hidEnv.mx)
+		assert.Nil(t, have.hidIO.mx)
+		assert.Fields(t, 10, Ring{})
 	})
 
 	t.Run("with option", func(t *testing.T) {
@@ -130,15 +250,41 @@ func Test_New(t *testing.T) {
 }
 
 func Test_Ring_Clock(t *testing.T) {
+	t.Run("clock", func(t *testing.T) {
+		// --- Given ---
+		custom := func() time.Time { return time.Time{} }
+		rng := &Ring{clock: Clock(custom)}
+
+		// --- When ---
+		have := rng.Clock()
+
+		// --- Then ---
+		assert.Same(t, custom, have)
+	})
+
+	t.Run("timekeeper", func(t *testing.T) {
+		// --- Given ---
+		now := time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+		rng := &Ring{clock: &tkNow{now: now}}
+
+		// --- When ---
+		have := rng.Clock()
+
+		// --- Then ---
+		assert.Equal(t, now, have())
+	})
+}
+
+func Test_Ring_Timekeeper(t *testing.T) {
 	// --- Given ---
-	custom := func() time.Time { return time.Time{} }
-	rng := &Ring{clock: custom}
+	tk := &tkNow{}
+	rng := New(WithTimekeeper(tk))
 
 	// --- When ---
-	have := rng.Clock()
+	have := rng.Timekeeper()
 
 	// --- Then ---
-	assert.Same(t, custom, have)
+	assert.Same(t, tk, have)
 }
 
 func Test_Ring_Args(t *testing.T) {
@@ -177,6 +323,30 @@ func Test_Ring_Name(t *testing.T) {
 	assert.Equal(t, "abc", have)
 }
 
+func Test_Ring_Concurrent(t *testing.T) {
+	t.Run("concurrent", func(t *testing.T) {
+		// --- Given ---
+		rng := New(WithConcurrency())
+
+		// --- When ---
+		have := rng.Concurrent()
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("not concurrent", func(t *testing.T) {
+		// --- Given ---
+		rng := New()
+
+		// --- When ---
+		have := rng.Concurrent()
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+}
+
 func Test_Ring_MetaSet(t *testing.T) {
 	t.Run("set", func(t *testing.T) {
 		// --- Given ---
@@ -276,15 +446,29 @@ func Test_Ring_MetaDelete(t *testing.T) {
 }
 
 func Test_Ring_MetaAll(t *testing.T) {
-	// --- Given ---
-	rng := &Ring{meta: map[string]any{"A": 1}}
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		rng := &Ring{meta: map[string]any{"A": 1}}
 
-	// --- When ---
-	have := rng.MetaAll()
+		// --- When ---
+		have := rng.MetaAll()
 
-	// --- Then ---
-	assert.Equal(t, map[string]any{"A": 1}, have)
-	assert.Same(t, rng.meta, have)
+		// --- Then ---
+		assert.Equal(t, map[string]any{"A": 1}, have)
+		assert.Same(t, rng.meta, have)
+	})
+
+	t.Run("concurrent returns a copy", func(t *testing.T) {
+		// --- Given ---
+		rng := New(WithConcurrency(), WithMeta(map[string]any{"A": 1}))
+
+		// --- When ---
+		have := rng.MetaAll()
+
+		// --- Then ---
+		assert.Equal(t, map[string]any{"A": 1}, have)
+		assert.NotSame(t, rng.meta, have)
+	})
 }
 
 func Test_Ring_FS(t *testing.T) {
@@ -313,11 +497,96 @@ func Test_Ring_FS(t *testing.T) {
 	})
 }
 
+func Test_Ring_WritableFS(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		wfs := must.Value(OpenRootFS(t.TempDir()))
+		t.Cleanup(func() { _ = wfs.Close() })
+		rng := New(WithWritableFS(wfs))
+
+		// --- When ---
+		have, err := rng.WritableFS()
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Same(t, wfs, have)
+	})
+
+	t.Run("error - no filesystem access", func(t *testing.T) {
+		// --- Given ---
+		rng := New(WithFS(os.DirFS("ringtest")))
+
+		// --- When ---
+		have, err := rng.WritableFS()
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrNoFsAccess, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Ring_LoadDotenv(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		fsys := fstest.MapFS{".env": {Data: []byte("A=file\nB=file")}}
+		rng := New(WithEnv([]string{"A=env"}), WithFS(fsys))
+
+		// --- When ---
+		err := rng.LoadDotenv(FillGaps, ".env")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"A=env", "B=file"}, Sort(rng.EnvAll()))
+	})
+
+	t.Run("error - no filesystem access", func(t *testing.T) {
+		// --- Given ---
+		rng := New(WithEnv([]string{"A=env"}))
+
+		// --- When ---
+		err := rng.LoadDotenv(FillGaps, ".env")
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrNoFsAccess, err)
+		assert.Equal(t, []string{"A=env"}, rng.EnvAll())
+	})
+}
+
+func Test_Ring_LoadEnvdir(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		fsys := fstest.MapFS{"env/A": {Data: []byte("file\n")}}
+		rng := New(WithEnv([]string{"B=env"}), WithFS(fsys))
+
+		// --- When ---
+		err := rng.LoadEnvdir("env", FillGaps, WithEnvdirSensitive())
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"A=file", "B=env"}, rng.EnvAll())
+		assert.True(t, rng.EnvIsSensitive("A"))
+	})
+
+	t.Run("error - no filesystem access", func(t *testing.T) {
+		// --- Given ---
+		rng := New(WithEnv([]string{"A=env"}))
+
+		// --- When ---
+		err := rng.LoadEnvdir("env", FillGaps)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrNoFsAccess, err)
+		assert.Equal(t, []string{"A=env"}, rng.EnvAll())
+	})
+}
+
 func Test_Ring_Clone(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		// --- Given ---
 		rngFS := os.DirFS("ringtest")
-		rng := New(WithFS(rngFS))
+		wfs := must.Value(OpenRootFS(t.TempDir()))
+		t.Cleanup(func() { _ = wfs.Close() })
+		rng := New(WithFS(rngFS), WithWritableFS(wfs))
 
 		// --- When ---
 		have := rng.Clone()
@@ -327,10 +596,130 @@ func Test_Ring_Clone(t *testing.T) {
 		assert.NotSame(t, rng.hidEnv, have.hidEnv)
 		assert.NotSame(t, rng.hidIO, have.hidIO)
 		assert.Same(t, rng.clock, have.clock)
+		assert.Same(t, rng.loc, have.loc)
 		assert.Equal(t, rng.name, have.name)
 		assert.Equal(t, rngFS, have.fs)
+		assert.Same(t, wfs, have.wfs)
 		assert.NotSame(t, rng.args, have.args)
 		assert.Same(t, rng.meta, have.meta)
-		assert.Fields(t, 7, Ring{})
+		assert.Nil(t, have.mx)
+		assert.Fields(t, 10, Ring{})
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		// --- Given ---
+		rng := New(WithConcurrency())
+
+		// --- When ---
+		have := rng.Clone()
+
+		// --- Then ---
+		assert.Same(t, rng.mx, have.mx)
+		assert.NotNil(t, have.hidEnv.mx)
+		assert.NotSame(t, rng.hidEnv.mx, have.hidEnv.mx)
+		assert.NotNil(t, have.hidIO.mx)
+		assert.NotSame(t, rng.hidIO.mx, have.hidIO.mx)
 	})
 }
+
+func Test_Ring_Derive(t *testing.T) {
+	// --- Given ---
+	rngFS := os.DirFS("ringtest")
+	rng := New(WithEnv([]string{"A=1", "B=2"}), WithFS(rngFS))
+
+	// --- When ---
+	have := rng.Derive()
+
+	// --- Then ---
+	assert.NotSame(t, rng, have)
+	assert.Same(t, rng.hidEnv, have.hidEnv.EnvParent())
+	assert.NotSame(t, rng.hidIO, have.hidIO)
+	assert.Same(t, rng.clock, have.clock)
+	assert.Equal(t, rng.name, have.name)
+	assert.Same(t, rng.loc, have.loc)
+	assert.Equal(t, rngFS, have.fs)
+	assert.Equal(t, rng.wfs, have.wfs)
+	assert.NotSame(t, rng.args, have.args)
+	assert.Same(t, rng.meta, have.meta)
+	assert.Fields(t, 10, Ring{})
+
+	have.EnvSet("A", "-1")
+	have.EnvUnset("B")
+	assert.Equal(t, []string{"A=-1"}, have.EnvAll())
+	assert.Equal(t, []string{"A=1", "B=2"}, Sort(rng.EnvAll()))
+}
+
+func Test_Ring_Derive_concurrent(t *testing.T) {
+	// --- Given ---
+	rng := New(WithConcurrency())
+
+	// --- When ---
+	have := rng.Derive()
+
+	// --- Then ---
+	assert.Same(t, rng.mx, have.mx)
+	assert.NotNil(t, have.hidEnv.mx)
+	assert.NotSame(t, rng.hidEnv.mx, have.hidEnv.mx)
+}
+
+func Test_Ring_String(t *testing.T) {
+	// --- Given ---
+	rng := New(
+		WithName("app"),
+		WithArgs([]string{"-v", "a b"}),
+		WithEnv([]string{"B=2", "A_TOKEN=abc"}),
+	)
+	rng.EnvSensitive("*_TOKEN")
+
+	// --- When ---
+	have := rng.String()
+
+	// --- Then ---
+	want := `name=app args=["-v" "a b"] env=[A_TOKEN=[REDACTED] B=2]`
+	assert.Equal(t, want, have)
+}
+
+func Test_Ring_GoString(t *testing.T) {
+	// --- Given ---
+	rng := New(
+		WithName("app"),
+		WithArgs([]string{"-v"}),
+		WithEnv([]string{"A_TOKEN=abc"}),
+	)
+	rng.EnvSensitive("*_TOKEN")
+
+	// --- When ---
+	have := fmt.Sprintf("%#v", rng)
+
+	// --- Then ---
+	want := `&ring.Ring{name:"app", args:[]string{"-v"}, ` +
+		`env:ring.NewEnv([]string{"A_TOKEN=[REDACTED]"})}`
+	assert.Equal(t, want, have)
+}
+
+func Test_Ring_LogValue(t *testing.T) {
+	// --- Given ---
+	rng := New(
+		WithName("app"),
+		WithArgs([]string{"-v"}),
+		WithEnv([]string{"B=2", "A_TOKEN=abc"}),
+	)
+	rng.EnvSensitive("*_TOKEN")
+	buf := &bytes.Buffer{}
+	log := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+
+	// --- When ---
+	log.Info("msg", "rng", rng)
+
+	// --- Then ---
+	want := "level=INFO msg=msg rng.name=app rng.args=[-v] " +
+		"rng.env.A_TOKEN=[REDACTED] rng.env.B=2\n"
+	assert.Equal(t, want, buf.String())
+}
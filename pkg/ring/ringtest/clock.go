@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ringtest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ctx42/ring/pkg/ring"
+)
+
+var _ ring.Timekeeper = &Clock{} // Compile time check.
+
+// Clock is a manually controlled [ring.Timekeeper] for tests. Its time
+// moves only when [Clock.Advance] or [Clock.Set] is called, which fire
+// pending timers and tickers in the order of their deadlines. Use
+// [Clock.BlockUntil] to wait for the code under test to start waiting on the
+// clock before advancing it.
+//
+// Example:
+//
+//	clk := ringtest.NewClock(time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC))
+//	tst := ringtest.New(t, ring.WithTimekeeper(clk))
+//	go run(tst.Ring()) // Waits 1s between retries.
+//	clk.BlockUntil(1)
+//	clk.Advance(time.Second)
+//
+// It is safe for concurrent use.
+type Clock struct {
+	now    time.Time    // Current time.
+	timers []*fakeTimer // Active timers and tickers.
+	seq    uint64       // Creation sequence of the last timer.
+	mx     sync.Mutex   // Guards all fields.
+	cond   *sync.Cond   // Signals changes of active timers.
+}
+
+// NewClock returns a new [Clock] set to the given time.
+func NewClock(start time.Time) *Clock {
+	clk := &Clock{now: start}
+	clk.cond = sync.NewCond(&clk.mx)
+	return clk
+}
+
+// Now returns the current time of the clock.
+func (clk *Clock) Now() time.Time {
+	clk.mx.Lock()
+	defer clk.mx.Unlock()
+	return clk.now
+}
+
+// Since returns the clock time elapsed since t.
+func (clk *Clock) Since(t time.Time) time.Duration { return clk.Now().Sub(t) }
+
+// Until returns the clock duration until t.
+func (clk *Clock) Until(t time.Time) time.Duration { return t.Sub(clk.Now()) }
+
+// Sleep blocks until the clock is advanced by at least the duration d.
+// Returns immediately for not positive durations.
+func (clk *Clock) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-clk.NewTimer(d).C()
+}
+
+// After returns a channel receiving the clock time once the clock is
+// advanced by at least the duration d.
+func (clk *Clock) After(d time.Duration) <-chan time.Time {
+	return clk.NewTimer(d).C()
+}
+
+// AfterFunc calls f once the clock is advanced by at least the duration d.
+// The function is called synchronously by [Clock.Advance] or [Clock.Set],
+// so its effects are visible when they return. For not positive durations,
+// the function is called in its own goroutine right away. The channel of the
+// returned [ring.Timer] is nil.
+func (clk *Clock) AfterFunc(d time.Duration, f func()) ring.Timer {
+	return clk.newTimer(d, 0, nil, f)
+}
+
+// NewTimer returns a [ring.Timer] sending the clock time on its channel once
+// the clock is advanced by at least the duration d. For not positive
+// durations, the time is sent right away.
+func (clk *Clock) NewTimer(d time.Duration) ring.Timer {
+	return clk.newTimer(d, 0, make(chan time.Time, 1), nil)
+}
+
+// NewTicker returns a [ring.Ticker] sending the clock time on its channel
+// each time the clock passes the next period. Like [time.Ticker], it drops
+// ticks for slow receivers. It panics if d is not greater than zero.
+func (clk *Clock) NewTicker(d time.Duration) ring.Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	return &fakeTicker{clk.newTimer(d, d, make(chan time.Time, 1), nil)}
+}
+
+// Advance moves the clock forward by the duration d, firing timers and
+// tickers with deadlines up to the new time in deadline order. While a
+// timer fires, the clock is set to its deadline. Not positive durations
+// only fire timers which are already due.
+func (clk *Clock) Advance(d time.Duration) {
+	clk.mx.Lock()
+	target := clk.now.Add(max(d, 0))
+	clk.mx.Unlock()
+	clk.advance(target)
+}
+
+// Set sets the clock to the given time. When the time is after the current
+// clock time, it works like [Clock.Advance], otherwise the clock is moved
+// back without firing any timers.
+func (clk *Clock) Set(tim time.Time) {
+	clk.mx.Lock()
+	if tim.Before(clk.now) {
+		clk.now = tim
+		clk.mx.Unlock()
+		return
+	}
+	clk.mx.Unlock()
+	clk.advance(tim)
+}
+
+// Waiters returns the number of active timers and tickers, including the
+// ones created by [Clock.Sleep] and [Clock.After].
+func (clk *Clock) Waiters() int {
+	clk.mx.Lock()
+	defer clk.mx.Unlock()
+	return len(clk.timers)
+}
+
+// BlockUntil blocks until there are at least n active timers and tickers,
+// see [Clock.Waiters]. Use it to wait for goroutines to start waiting on the
+// clock before advancing it.
+func (clk *Clock) BlockUntil(n int) {
+	clk.mx.Lock()
+	defer clk.mx.Unlock()
+	for len(clk.timers) < n {
+		clk.cond.Wait()
+	}
+}
+
+// newTimer creates and registers a new timer. The period is zero for
+// one-shot timers.
+func (clk *Clock) newTimer(
+	d, period time.Duration,
+	ch chan time.Time,
+	fn func(),
+) *fakeTimer {
+	tmr := &fakeTimer{clk: clk, ch: ch, fn: fn, period: period}
+	clk.mx.Lock()
+	defer clk.mx.Unlock()
+	clk.arm(tmr, d)
+	return tmr
+}
+
+// arm schedules the timer to fire after the duration d. Timers with not
+// positive durations fire right away. The caller must hold the lock.
+func (clk *Clock) arm(tmr *fakeTimer, d time.Duration) {
+	if d <= 0 {
+		if tmr.fn != nil {
+			go tmr.fn()
+		} else {
+			tmr.send(clk.now)
+		}
+		return
+	}
+	clk.seq++
+	tmr.seq = clk.seq
+	tmr.deadline = clk.now.Add(d)
+	clk.timers = append(clk.timers, tmr)
+	clk.cond.Broadcast()
+}
+
+// disarm removes the timer from active timers and reports if it was active.
+// The caller must hold the lock.
+func (clk *Clock) disarm(tmr *fakeTimer) bool {
+	for i, t := range clk.timers {
+		if t == tmr {
+			clk.timers = append(clk.timers[:i], clk.timers[i+1:]...)
+			clk.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// advance fires timers with deadlines up to the target time in deadline
+// order and sets the clock to the target time.
+func (clk *Clock) advance(target time.Time) {
+	for {
+		clk.mx.Lock()
+		tmr := clk.next(target)
+		if tmr == nil {
+			if target.After(clk.now) {
+				clk.now = target
+			}
+			clk.mx.Unlock()
+			return
+		}
+		if tmr.deadline.After(clk.now) {
+			clk.now = tmr.deadline
+		}
+		fn := tmr.fn
+		if tmr.period > 0 {
+			clk.seq++
+			tmr.seq = clk.seq
+			tmr.deadline = tmr.deadline.Add(tmr.period)
+		} else {
+			clk.disarm(tmr)
+		}
+		if tmr.ch != nil {
+			tmr.send(clk.now)
+		}
+		clk.mx.Unlock()
+		if fn != nil {
+			fn()
+		}
+	}
+}
+
+// next returns the active timer with the earliest deadline not after the
+// target time, or nil. Timers with the same deadline are returned in the
+// order they were scheduled. The caller must hold the lock.
+func (clk *Clock) next(target time.Time) *fakeTimer {
+	var ret *fakeTimer
+	for _, tmr := range clk.timers {
+		if tmr.deadline.After(target) {
+			continue
+		}
+		if ret == nil || tmr.deadline.Before(ret.deadline) ||
+			(tmr.deadline.Equal(ret.deadline) && tmr.seq < ret.seq) {
+			ret = tmr
+		}
+	}
+	return ret
+}
+
+// fakeTimer implements [ring.Timer] for [Clock].
+type fakeTimer struct {
+	clk      *Clock         // The clock the timer belongs to.
+	ch       chan time.Time // Channel receiving the time, nil for funcs.
+	fn       func()         // Function to call, nil for channel timers.
+	period   time.Duration  // Ticker period, zero for one-shot timers.
+	deadline time.Time      // When the timer fires next.
+	seq      uint64         // Scheduling sequence.
+}
+
+// C returns the channel on which the time is delivered.
+func (tmr *fakeTimer) C() <-chan time.Time { return tmr.ch }
+
+// Stop stops the timer. It returns true if the timer was active. After Stop,
+// no stale values are received from the channel.
+func (tmr *fakeTimer) Stop() bool {
+	tmr.clk.mx.Lock()
+	defer tmr.clk.mx.Unlock()
+	tmr.drain()
+	return tmr.clk.disarm(tmr)
+}
+
+// Reset changes the timer to fire after the duration d. For tickers, it
+// also changes the period. Returns true if the timer was active. After
+// Reset, no stale values are received from the channel.
+func (tmr *fakeTimer) Reset(d time.Duration) bool {
+	tmr.clk.mx.Lock()
+	defer tmr.clk.mx.Unlock()
+	if tmr.period > 0 {
+		tmr.period = d
+	}
+	tmr.drain()
+	active := tmr.clk.disarm(tmr)
+	tmr.clk.arm(tmr, d)
+	return active
+}
+
+// send sends the time on the channel without blocking.
+func (tmr *fakeTimer) send(tim time.Time) {
+	select {
+	case tmr.ch <- tim:
+	default:
+	}
+}
+
+// drain removes a not received value from the channel.
+func (tmr *fakeTimer) drain() {
+	select {
+	case <-tmr.ch:
+	default:
+	}
+}
+
+// fakeTicker implements [ring.Ticker] for [Clock].
+type fakeTicker struct{ *fakeTimer }
+
+// Stop turns off the ticker. After Stop, no more ticks are received.
+func (tck *fakeTicker) Stop() { tck.fakeTimer.Stop() }
+
+// Reset stops the ticker and resets its period to the duration d. It panics
+// if d is not greater than zero.
+func (tck *fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+	tck.fakeTimer.Reset(d)
+}
@@ -0,0 +1,534 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ringtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/tester"
+
+	"github.com/ctx42/ring/pkg/ring"
+)
+
+// clockStart is the start time of clocks used in tests.
+var clockStart = time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func Test_NewClock(t *testing.T) {
+	// --- When ---
+	have := NewClock(clockStart)
+
+	// --- Then ---
+	assert.Equal(t, clockStart, have.now)
+	assert.Len(t, 0, have.timers)
+	assert.NotNil(t, have.cond)
+	assert.Equal(t, clockStart, have.Now())
+}
+
+func Test_Clock_Since(t *testing.T) {
+	// --- Given ---
+	clk := NewClock(clockStart)
+
+	// --- When ---
+	have := clk.Since(clockStart.Add(-time.Hour))
+
+	// --- Then ---
+	assert.Equal(t, time.Hour, have)
+}
+
+func Test_Clock_Until(t *testing.T) {
+	// --- Given ---
+	clk := NewClock(clockStart)
+
+	// --- When ---
+	have := clk.Until(clockStart.Add(time.Minute))
+
+	// --- Then ---
+	assert.Equal(t, time.Minute, have)
+}
+
+func Test_Clock_Sleep(t *testing.T) {
+	t.Run("wakes up when advanced", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		var woke time.Time
+		var wg sync.WaitGroup
+		wg.Go(func() {
+			clk.Sleep(time.Second)
+			woke = clk.Now()
+		})
+		clk.BlockUntil(1)
+
+		// --- When ---
+		clk.Advance(time.Second)
+
+		// --- Then ---
+		wg.Wait()
+		assert.Equal(t, clockStart.Add(time.Second), woke)
+		assert.Equal(t, 0, clk.Waiters())
+	})
+
+	t.Run("not positive duration", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+
+		// --- When ---
+		clk.Sleep(0)
+
+		// --- Then ---
+		assert.Equal(t, 0, clk.Waiters())
+		assert.Equal(t, clockStart, clk.Now())
+	})
+}
+
+func Test_Clock_After(t *testing.T) {
+	t.Run("fires", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		ch := clk.After(time.Minute)
+
+		// --- When ---
+		clk.Advance(2 * time.Minute)
+
+		// --- Then ---
+		assert.Equal(t, clockStart.Add(time.Minute), <-ch)
+		assert.Equal(t, clockStart.Add(2*time.Minute), clk.Now())
+	})
+
+	t.Run("not fired before deadline", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		ch := clk.After(time.Minute)
+
+		// --- When ---
+		clk.Advance(time.Minute - 1)
+
+		// --- Then ---
+		assert.Len(t, 0, ch)
+		assert.Equal(t, 1, clk.Waiters())
+	})
+}
+
+func Test_Clock_AfterFunc(t *testing.T) {
+	t.Run("called synchronously", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		var at time.Time
+		tmr := clk.AfterFunc(time.Second, func() { at = clk.Now() })
+
+		// --- When ---
+		clk.Advance(time.Hour)
+
+		// --- Then ---
+		assert.Equal(t, clockStart.Add(time.Second), at)
+		assert.Nil(t, tmr.C())
+		assert.False(t, tmr.Stop())
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		var called bool
+		tmr := clk.AfterFunc(time.Second, func() { called = true })
+
+		// --- When ---
+		stopped := tmr.Stop()
+
+		// --- Then ---
+		clk.Advance(time.Hour)
+		assert.True(t, stopped)
+		assert.False(t, called)
+	})
+
+	t.Run("function may use the clock", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		var fired []time.Time
+		var fn func()
+		fn = func() {
+			fired = append(fired, clk.Now())
+			clk.AfterFunc(time.Second, fn)
+		}
+		clk.AfterFunc(time.Second, fn)
+
+		// --- When ---
+		clk.Advance(3 * time.Second)
+
+		// --- Then ---
+		want := []time.Time{
+			clockStart.Add(time.Second),
+			clockStart.Add(2 * time.Second),
+			clockStart.Add(3 * time.Second),
+		}
+		assert.Equal(t, want, fired)
+		assert.Equal(t, 1, clk.Waiters())
+	})
+
+	t.Run("not positive duration", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		done := make(chan struct{})
+
+		// --- When ---
+		clk.AfterFunc(0, func() { close(done) })
+
+		// --- Then ---
+		<-done
+		assert.Equal(t, 0, clk.Waiters())
+	})
+}
+
+func Test_Clock_NewTimer(t *testing.T) {
+	t.Run("fires once", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		tmr := clk.NewTimer(time.Second)
+
+		// --- When ---
+		clk.Advance(5 * time.Second)
+
+		// --- Then ---
+		assert.Equal(t, clockStart.Add(time.Second), <-tmr.C())
+		assert.Len(t, 0, tmr.C())
+		assert.False(t, tmr.Stop())
+	})
+
+	t.Run("not positive duration", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+
+		// --- When ---
+		tmr := clk.NewTimer(-time.Second)
+
+		// --- Then ---
+		assert.Equal(t, clockStart, <-tmr.C())
+		assert.Equal(t, 0, clk.Waiters())
+	})
+
+	t.Run("stop drains the channel", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		tmr := clk.NewTimer(time.Second)
+		clk.Advance(time.Second)
+
+		// --- When ---
+		stopped := tmr.Stop()
+
+		// --- Then ---
+		assert.False(t, stopped)
+		assert.Len(t, 0, tmr.C())
+	})
+
+	t.Run("reset active", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		tmr := clk.NewTimer(time.Second)
+
+		// --- When ---
+		active := tmr.Reset(time.Minute)
+
+		// --- Then ---
+		assert.True(t, active)
+		clk.Advance(time.Second)
+		assert.Len(t, 0, tmr.C())
+		clk.Advance(time.Minute)
+		assert.Equal(t, clockStart.Add(time.Minute), <-tmr.C())
+	})
+
+	t.Run("reset expired", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		tmr := clk.NewTimer(time.Second)
+		clk.Advance(time.Second)
+
+		// --- When ---
+		active := tmr.Reset(time.Second)
+
+		// --- Then ---
+		assert.False(t, active)
+		assert.Len(t, 0, tmr.C())
+		clk.Advance(time.Second)
+		assert.Equal(t, clockStart.Add(2*time.Second), <-tmr.C())
+	})
+}
+
+func Test_Clock_NewTicker(t *testing.T) {
+	t.Run("ticks", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		tck := clk.NewTicker(time.Second)
+
+		// --- When ---
+		clk.Advance(time.Second)
+		first := <-tck.C()
+		clk.Advance(time.Second)
+		second := <-tck.C()
+
+		// --- Then ---
+		assert.Equal(t, clockStart.Add(time.Second), first)
+		assert.Equal(t, clockStart.Add(2*time.Second), second)
+		assert.Equal(t, 1, clk.Waiters())
+	})
+
+	t.Run("drops ticks for slow receivers", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		tck := clk.NewTicker(time.Second)
+
+		// --- When ---
+		clk.Advance(3 * time.Second)
+
+		// --- Then ---
+		assert.Equal(t, clockStart.Add(time.Second), <-tck.C())
+		assert.Len(t, 0, tck.C())
+		clk.Advance(time.Second)
+		assert.Equal(t, clockStart.Add(4*time.Second), <-tck.C())
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		tck := clk.NewTicker(time.Second)
+		clk.Advance(time.Second)
+
+		// --- When ---
+		tck.Stop()
+
+		// --- Then ---
+		clk.Advance(time.Second)
+		assert.Len(t, 0, tck.C())
+		assert.Equal(t, 0, clk.Waiters())
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		tck := clk.NewTicker(time.Second)
+
+		// --- When ---
+		tck.Reset(time.Minute)
+
+		// --- Then ---
+		clk.Advance(time.Second)
+		assert.Len(t, 0, tck.C())
+		clk.Advance(time.Minute)
+		assert.Equal(t, clockStart.Add(time.Minute), <-tck.C())
+		clk.Advance(time.Minute)
+		want := clockStart.Add(time.Minute + time.Minute)
+		assert.Equal(t, want, <-tck.C())
+	})
+
+	t.Run("panics for not positive duration", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+
+		// --- Then ---
+		wMsg := "non-positive interval for NewTicker"
+		assert.PanicMsg(t, wMsg, func() { clk.NewTicker(0) })
+	})
+
+	t.Run("reset panics for not positive duration", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		tck := clk.NewTicker(time.Second)
+
+		// --- Then ---
+		wMsg := "non-positive interval for Ticker.Reset"
+		assert.PanicMsg(t, wMsg, func() { tck.Reset(0) })
+	})
+}
+
+func Test_Clock_Advance(t *testing.T) {
+	t.Run("fires in deadline order", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		var order []string
+		clk.AfterFunc(3*time.Second, func() { order = append(order, "c") })
+		clk.AfterFunc(time.Second, func() { order = append(order, "a") })
+		clk.AfterFunc(2*time.Second, func() { order = append(order, "b1") })
+		clk.AfterFunc(2*time.Second, func() { order = append(order, "b2") })
+		clk.AfterFunc(time.Hour, func() { order = append(order, "x") })
+
+		// --- When ---
+		clk.Advance(3 * time.Second)
+
+		// --- Then ---
+		assert.Equal(t, []string{"a", "b1", "b2", "c"}, order)
+		assert.Equal(t, clockStart.Add(3*time.Second), clk.Now())
+		assert.Equal(t, 1, clk.Waiters())
+	})
+
+	t.Run("timers and tickers interleave", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		var order []string
+		tck := clk.NewTicker(2 * time.Second)
+		defer tck.Stop()
+		clk.AfterFunc(3*time.Second, func() {
+			order = append(order, "timer")
+			<-tck.C()
+			order = append(order, "tick")
+		})
+
+		// --- When ---
+		clk.Advance(3 * time.Second)
+
+		// --- Then ---
+		assert.Equal(t, []string{"timer", "tick"}, order)
+	})
+
+	t.Run("negative duration", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+
+		// --- When ---
+		clk.Advance(-time.Second)
+
+		// --- Then ---
+		assert.Equal(t, clockStart, clk.Now())
+	})
+}
+
+func Test_Clock_Set(t *testing.T) {
+	t.Run("forward", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		ch := clk.After(time.Second)
+		tim := clockStart.Add(time.Hour)
+
+		// --- When ---
+		clk.Set(tim)
+
+		// --- Then ---
+		assert.Equal(t, tim, clk.Now())
+		assert.Equal(t, clockStart.Add(time.Second), <-ch)
+	})
+
+	t.Run("backward", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		ch := clk.After(time.Second)
+		tim := clockStart.Add(-time.Hour)
+
+		// --- When ---
+		clk.Set(tim)
+
+		// --- Then ---
+		assert.Equal(t, tim, clk.Now())
+		assert.Len(t, 0, ch)
+		assert.Equal(t, 1, clk.Waiters())
+	})
+}
+
+func Test_Clock_BlockUntil(t *testing.T) {
+	t.Run("waits for goroutines", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		var wg sync.WaitGroup
+		for range 3 {
+			wg.Go(func() { clk.Sleep(time.Second) })
+		}
+
+		// --- When ---
+		clk.BlockUntil(3)
+
+		// --- Then ---
+		assert.Equal(t, 3, clk.Waiters())
+		clk.Advance(time.Second)
+		wg.Wait()
+		assert.Equal(t, 0, clk.Waiters())
+	})
+
+	t.Run("already waiting", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+		clk.NewTimer(time.Second)
+		clk.NewTicker(time.Second)
+
+		// --- When ---
+		clk.BlockUntil(2)
+
+		// --- Then ---
+		assert.Equal(t, 2, clk.Waiters())
+	})
+}
+
+func Test_Clock_with_Tester(t *testing.T) {
+	// --- Given ---
+	tspy := tester.New(t)
+	tspy.ExpectCleanups(2)
+	tspy.Close()
+
+	clk := NewClock(clockStart)
+	tst := New(tspy, ring.WithTimekeeper(clk))
+	rng := tst.Ring()
+
+	// --- When ---
+	clk.Advance(time.Minute)
+
+	// --- Then ---
+	assert.Same(t, clk, rng.Timekeeper())
+	assert.Equal(t, clockStart.Add(time.Minute), rng.Clock()())
+}
+
+func Test_Clock_with_Retry(t *testing.T) {
+	// --- Given ---
+	clk := NewClock(clockStart)
+	rng := ring.New(ring.WithTimekeeper(clk))
+	var calls []time.Time
+	fn := func(context.Context) error {
+		calls = append(calls, clk.Now())
+		return errors.New("e")
+	}
+	opts := []ring.RetryOption{
+		ring.WithRetryBackoff(ring.ExponentialBackoff(time.Second, 0)),
+		ring.WithRetryMaxAttempts(3),
+	}
+
+	var wg sync.WaitGroup
+	var err error
+	wg.Go(func() { err = rng.Retry(context.Background(), fn, opts...) })
+
+	// --- When ---
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	clk.BlockUntil(1)
+	clk.Advance(2 * time.Second)
+
+	// --- Then ---
+	wg.Wait()
+	assert.ErrorIs(t, ring.ErrRetry, err)
+	want := []time.Time{
+		clockStart,
+		clockStart.Add(time.Second),
+		clockStart.Add(3 * time.Second),
+	}
+	assert.Equal(t, want, calls)
+	assert.Equal(t, 0, clk.Waiters())
+}
+
+func Test_Clock_with_TimeoutContext(t *testing.T) {
+	// --- Given ---
+	clk := NewClock(clockStart)
+	rng := ring.New(ring.WithTimekeeper(clk))
+	ctx, cancel := rng.TimeoutContext(context.Background(), time.Minute)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var err error
+	wg.Go(func() { err = rng.SleepContext(ctx, time.Hour) })
+
+	// --- When ---
+	clk.BlockUntil(2)
+	clk.Advance(time.Minute)
+
+	// --- Then ---
+	wg.Wait()
+	assert.ErrorIs(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 0, clk.Waiters())
+}
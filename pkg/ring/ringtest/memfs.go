@@ -0,0 +1,730 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ringtest
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/tester"
+
+	"github.com/ctx42/ring/pkg/ring"
+)
+
+// Compile time checks.
+var (
+	_ ring.WritableFS = &MemFS{}
+	_ fs.StatFS       = &MemFS{}
+	_ fs.ReadFileFS   = &MemFS{}
+	_ fs.ReadDirFS    = &MemFS{}
+	_ fs.ReadLinkFS   = &MemFS{}
+)
+
+// maxLinks is the maximum number of symbolic links followed in a path.
+const maxLinks = 40
+
+// MemFS errors.
+var (
+	errNotDir   = errors.New("not a directory")
+	errIsDir    = errors.New("is a directory")
+	errNotEmpty = errors.New("directory not empty")
+	errNotLink  = errors.New("not a symbolic link")
+	errEscape   = errors.New("path escapes from root")
+	errLoop     = errors.New("too many levels of symbolic links")
+)
+
+// MemFS is an in-memory [ring.WritableFS] for tests. It supports
+// directories, symbolic links and permissions. Modification times are set
+// using the [ring.Timekeeper], so with [Clock] they are deterministic.
+//
+// Permissions are stored as given, there is no umask. Only the owner bits
+// are checked: reading a file or listing a directory requires the read
+// permission, writing a file or adding, removing and renaming directory
+// entries requires the write permission.
+//
+// Symbolic links are followed, but they must not be absolute or point
+// outside the filesystem.
+//
+// Example:
+//
+//	mfs := ringtest.NewMemFS(clk)
+//	tst := ringtest.New(t, ring.WithWritableFS(mfs), ring.WithFS(mfs))
+//	err := run(tst.Ring()) // Writes "out/report.txt".
+//	mfs.AssertFile(t, "out/report.txt", "ok\n")
+//
+// It is safe for concurrent use.
+type MemFS struct {
+	tk   ring.Timekeeper // Source of modification times.
+	root *memNode        // Root directory.
+	mx   sync.RWMutex    // Guards the tree.
+}
+
+// NewMemFS returns a new [MemFS] with an empty root directory with 0755
+// permissions. Modification times are set using the [ring.Timekeeper], when
+// nil, [ring.NowUTC] is used.
+func NewMemFS(tk ring.Timekeeper) *MemFS {
+	if tk == nil {
+		tk = ring.Clock(ring.NowUTC)
+	}
+	return &MemFS{tk: tk, root: newDir(0o755, tk.Now())}
+}
+
+// Open opens the named file for reading, see [fs.FS].
+func (mfs *MemFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, pathError("open", name, fs.ErrInvalid)
+	}
+	mfs.mx.RLock()
+	defer mfs.mx.RUnlock()
+	node, err := mfs.walk(name, true)
+	if err != nil {
+		return nil, pathError("open", name, err)
+	}
+	if node.mode&0o400 == 0 {
+		return nil, pathError("open", name, fs.ErrPermission)
+	}
+	info := node.info(path.Base(name))
+	if node.mode.IsDir() {
+		return &memDir{info: info, entries: node.entries()}, nil
+	}
+	return &memFile{Reader: bytes.NewReader(node.data), info: info}, nil
+}
+
+// Stat returns a [fs.FileInfo] describing the named file, see [fs.StatFS].
+func (mfs *MemFS) Stat(name string) (fs.FileInfo, error) {
+	return mfs.stat("stat", name, true)
+}
+
+// Lstat returns a [fs.FileInfo] describing the named file without following
+// symbolic links, see [fs.ReadLinkFS].
+func (mfs *MemFS) Lstat(name string) (fs.FileInfo, error) {
+	return mfs.stat("lstat", name, false)
+}
+
+// ReadFile reads the named file, see [fs.ReadFileFS].
+func (mfs *MemFS) ReadFile(name string) ([]byte, error) {
+	if !fs.ValidPath(name) {
+		return nil, pathError("readfile", name, fs.ErrInvalid)
+	}
+	mfs.mx.RLock()
+	defer mfs.mx.RUnlock()
+	node, err := mfs.walk(name, true)
+	if err != nil {
+		return nil, pathError("readfile", name, err)
+	}
+	if node.mode.IsDir() {
+		return nil, pathError("readfile", name, errIsDir)
+	}
+	if node.mode&0o400 == 0 {
+		return nil, pathError("readfile", name, fs.ErrPermission)
+	}
+	return bytes.Clone(node.data), nil
+}
+
+// ReadDir reads the named directory returning entries sorted by name, see
+// [fs.ReadDirFS].
+func (mfs *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, pathError("readdir", name, fs.ErrInvalid)
+	}
+	mfs.mx.RLock()
+	defer mfs.mx.RUnlock()
+	node, err := mfs.walk(name, true)
+	if err != nil {
+		return nil, pathError("readdir", name, err)
+	}
+	if !node.mode.IsDir() {
+		return nil, pathError("readdir", name, errNotDir)
+	}
+	if node.mode&0o400 == 0 {
+		return nil, pathError("readdir", name, fs.ErrPermission)
+	}
+	return node.entries(), nil
+}
+
+// ReadLink returns the destination of the named symbolic link, see
+// [fs.ReadLinkFS].
+func (mfs *MemFS) ReadLink(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", pathError("readlink", name, fs.ErrInvalid)
+	}
+	mfs.mx.RLock()
+	defer mfs.mx.RUnlock()
+	node, err := mfs.walk(name, false)
+	if err != nil {
+		return "", pathError("readlink", name, err)
+	}
+	if node.mode.Type() != fs.ModeSymlink {
+		return "", pathError("readlink", name, errNotLink)
+	}
+	return string(node.data), nil
+}
+
+// Create implements [ring.WritableFS].
+func (mfs *MemFS) Create(name string) (ring.WritableFile, error) {
+	node, err := mfs.write("create", name, nil, 0o666, false)
+	if err != nil {
+		return nil, err
+	}
+	return &memWriter{mfs: mfs, node: node, name: name}, nil
+}
+
+// WriteFile implements [ring.WritableFS].
+func (mfs *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	_, err := mfs.write("writefile", name, data, perm, true)
+	return err
+}
+
+// Mkdir implements [ring.WritableFS].
+func (mfs *MemFS) Mkdir(name string, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return pathError("mkdir", name, fs.ErrInvalid)
+	}
+	now := mfs.tk.Now()
+	mfs.mx.Lock()
+	defer mfs.mx.Unlock()
+	if err := mfs.add(name, newDir(perm, now), now); err != nil {
+		return pathError("mkdir", name, err)
+	}
+	return nil
+}
+
+// MkdirAll implements [ring.WritableFS].
+func (mfs *MemFS) MkdirAll(name string, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return pathError("mkdirall", name, fs.ErrInvalid)
+	}
+	now := mfs.tk.Now()
+	mfs.mx.Lock()
+	defer mfs.mx.Unlock()
+	var dir string
+	for _, part := range splitPath(name) {
+		dir = path.Join(dir, part)
+		node, err := mfs.walk(dir, true)
+		if err == nil {
+			if !node.mode.IsDir() {
+				return pathError("mkdirall", dir, errNotDir)
+			}
+			continue
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return pathError("mkdirall", dir, err)
+		}
+		if err = mfs.add(dir, newDir(perm, now), now); err != nil {
+			return pathError("mkdirall", dir, err)
+		}
+	}
+	return nil
+}
+
+// Remove implements [ring.WritableFS].
+func (mfs *MemFS) Remove(name string) error {
+	if !fs.ValidPath(name) || name == "." {
+		return pathError("remove", name, fs.ErrInvalid)
+	}
+	now := mfs.tk.Now()
+	mfs.mx.Lock()
+	defer mfs.mx.Unlock()
+	dir, base, err := mfs.parent(name)
+	if err != nil {
+		return pathError("remove", name, err)
+	}
+	node, ok := dir.children[base]
+	if !ok {
+		return pathError("remove", name, fs.ErrNotExist)
+	}
+	if len(node.children) > 0 {
+		return pathError("remove", name, errNotEmpty)
+	}
+	if dir.mode&0o200 == 0 {
+		return pathError("remove", name, fs.ErrPermission)
+	}
+	delete(dir.children, base)
+	dir.modTime = now
+	return nil
+}
+
+// RemoveAll implements [ring.WritableFS].
+func (mfs *MemFS) RemoveAll(name string) error {
+	if !fs.ValidPath(name) || name == "." {
+		return pathError("removeall", name, fs.ErrInvalid)
+	}
+	now := mfs.tk.Now()
+	mfs.mx.Lock()
+	defer mfs.mx.Unlock()
+	dir, base, err := mfs.parent(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return pathError("removeall", name, err)
+	}
+	if _, ok := dir.children[base]; !ok {
+		return nil
+	}
+	if dir.mode&0o200 == 0 {
+		return pathError("removeall", name, fs.ErrPermission)
+	}
+	delete(dir.children, base)
+	dir.modTime = now
+	return nil
+}
+
+// Rename implements [ring.WritableFS].
+func (mfs *MemFS) Rename(oldname, newname string) error {
+	if !fs.ValidPath(oldname) || !fs.ValidPath(newname) ||
+		oldname == "." || newname == "." {
+		return linkError("rename", oldname, newname, fs.ErrInvalid)
+	}
+	now := mfs.tk.Now()
+	mfs.mx.Lock()
+	defer mfs.mx.Unlock()
+	if err := mfs.rename(oldname, newname, now); err != nil {
+		return linkError("rename", oldname, newname, err)
+	}
+	return nil
+}
+
+// Chmod implements [ring.WritableFS].
+func (mfs *MemFS) Chmod(name string, mode fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return pathError("chmod", name, fs.ErrInvalid)
+	}
+	mfs.mx.Lock()
+	defer mfs.mx.Unlock()
+	node, err := mfs.walk(name, true)
+	if err != nil {
+		return pathError("chmod", name, err)
+	}
+	node.mode = node.mode&^fs.ModePerm | mode&fs.ModePerm
+	return nil
+}
+
+// Symlink implements [ring.WritableFS].
+func (mfs *MemFS) Symlink(oldname, newname string) error {
+	if !fs.ValidPath(newname) {
+		return linkError("symlink", oldname, newname, fs.ErrInvalid)
+	}
+	now := mfs.tk.Now()
+	mfs.mx.Lock()
+	defer mfs.mx.Unlock()
+	node := &memNode{
+		mode:    fs.ModeSymlink | 0o777,
+		data:    []byte(oldname),
+		modTime: now,
+	}
+	if err := mfs.add(newname, node, now); err != nil {
+		return linkError("symlink", oldname, newname, err)
+	}
+	return nil
+}
+
+// Tree returns paths of all files in the filesystem in lexical order.
+// Directory paths end with "/" and symbolic links are followed by " -> " and
+// their destination, for example:
+//
+//	[]string{"dir/", "dir/file.txt", "link -> dir/file.txt"}
+//
+// Returns nil for an empty filesystem.
+func (mfs *MemFS) Tree() []string {
+	mfs.mx.RLock()
+	defer mfs.mx.RUnlock()
+	var tree []string
+	var visit func(dir *memNode, prefix string)
+	visit = func(dir *memNode, prefix string) {
+		for _, name := range slices.Sorted(maps.Keys(dir.children)) {
+			node := dir.children[name]
+			switch node.mode.Type() {
+			case fs.ModeDir:
+				tree = append(tree, prefix+name+"/")
+				visit(node, prefix+name+"/")
+			case fs.ModeSymlink:
+				tree = append(tree, prefix+name+" -> "+string(node.data))
+			default:
+				tree = append(tree, prefix+name)
+			}
+		}
+	}
+	visit(mfs.root, "")
+	return tree
+}
+
+// AssertExist asserts the named file exists. Symbolic links are not
+// followed.
+func (mfs *MemFS) AssertExist(t tester.T, name string) bool {
+	t.Helper()
+	_, err := mfs.Lstat(name)
+	return assert.NoError(t, err)
+}
+
+// AssertNotExist asserts the named file does not exist. Symbolic links are
+// not followed.
+func (mfs *MemFS) AssertNotExist(t tester.T, name string) bool {
+	t.Helper()
+	_, err := mfs.Lstat(name)
+	return assert.ErrorIs(t, fs.ErrNotExist, err)
+}
+
+// AssertFile asserts the named file exists and has the given content.
+func (mfs *MemFS) AssertFile(t tester.T, name, want string) bool {
+	t.Helper()
+	data, err := mfs.ReadFile(name)
+	if !assert.NoError(t, err) {
+		return false
+	}
+	return assert.Equal(t, want, string(data))
+}
+
+// stat returns a [fs.FileInfo] describing the named file. When follow is
+// true, the symbolic link as the last path element is followed.
+func (mfs *MemFS) stat(op, name string, follow bool) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, pathError(op, name, fs.ErrInvalid)
+	}
+	mfs.mx.RLock()
+	defer mfs.mx.RUnlock()
+	node, err := mfs.walk(name, follow)
+	if err != nil {
+		return nil, pathError(op, name, err)
+	}
+	return node.info(path.Base(name)), nil
+}
+
+// write writes data to the named file, creating it with the given
+// permissions if it does not exist. When replace is false, data is ignored
+// and the file is truncated.
+func (mfs *MemFS) write(
+	op, name string,
+	data []byte,
+	perm fs.FileMode,
+	replace bool,
+) (*memNode, error) {
+	if !fs.ValidPath(name) {
+		return nil, pathError(op, name, fs.ErrInvalid)
+	}
+	now := mfs.tk.Now()
+	mfs.mx.Lock()
+	defer mfs.mx.Unlock()
+	node, err := mfs.walk(name, true)
+	switch {
+	case err == nil:
+		if node.mode.IsDir() {
+			return nil, pathError(op, name, errIsDir)
+		}
+		if node.mode&0o200 == 0 {
+			return nil, pathError(op, name, fs.ErrPermission)
+		}
+	case errors.Is(err, fs.ErrNotExist):
+		node = &memNode{mode: perm & fs.ModePerm}
+		if err = mfs.add(name, node, now); err != nil {
+			return nil, pathError(op, name, err)
+		}
+	default:
+		return nil, pathError(op, name, err)
+	}
+	node.data = nil
+	if replace {
+		node.data = bytes.Clone(data)
+	}
+	node.modTime = now
+	return node, nil
+}
+
+// rename renames oldname to newname. The caller must hold the write lock.
+func (mfs *MemFS) rename(oldname, newname string, now time.Time) error {
+	srcDir, srcBase, err := mfs.parent(oldname)
+	if err != nil {
+		return err
+	}
+	node, ok := srcDir.children[srcBase]
+	if !ok {
+		return fs.ErrNotExist
+	}
+	dstDir, dstBase, err := mfs.parent(newname)
+	if err != nil {
+		return err
+	}
+	if srcDir.mode&0o200 == 0 || dstDir.mode&0o200 == 0 {
+		return fs.ErrPermission
+	}
+	if srcDir == dstDir && srcBase == dstBase {
+		return nil
+	}
+	if node.mode.IsDir() && node.contains(dstDir) {
+		return fs.ErrInvalid
+	}
+	if dst, ok := dstDir.children[dstBase]; ok {
+		switch {
+		case dst.mode.IsDir() && !node.mode.IsDir():
+			return errIsDir
+		case !dst.mode.IsDir() && node.mode.IsDir():
+			return errNotDir
+		case len(dst.children) > 0:
+			return errNotEmpty
+		}
+	}
+	delete(srcDir.children, srcBase)
+	dstDir.children[dstBase] = node
+	srcDir.modTime = now
+	dstDir.modTime = now
+	return nil
+}
+
+// add adds the node as the named file. It returns an error wrapping
+// [fs.ErrExist] when the file already exists. The caller must hold the write
+// lock.
+func (mfs *MemFS) add(name string, node *memNode, now time.Time) error {
+	if name == "." {
+		return fs.ErrExist
+	}
+	dir, base, err := mfs.parent(name)
+	if err != nil {
+		return err
+	}
+	if _, ok := dir.children[base]; ok {
+		return fs.ErrExist
+	}
+	if dir.mode&0o200 == 0 {
+		return fs.ErrPermission
+	}
+	node.modTime = now
+	dir.children[base] = node
+	dir.modTime = now
+	return nil
+}
+
+// parent returns the parent directory of the named file and the last
+// element of the name. The caller must hold the lock.
+func (mfs *MemFS) parent(name string) (*memNode, string, error) {
+	dir, err := mfs.walk(path.Dir(name), true)
+	if err != nil {
+		return nil, "", err
+	}
+	if !dir.mode.IsDir() {
+		return nil, "", errNotDir
+	}
+	return dir, path.Base(name), nil
+}
+
+// walk returns the node of the named file. Symbolic links are followed,
+// except the last path element when follow is false. The caller must hold
+// the lock.
+func (mfs *MemFS) walk(name string, follow bool) (*memNode, error) {
+	parts := splitPath(name)
+	stack := []*memNode{mfs.root}
+	for links := 0; len(parts) > 0; {
+		part := parts[0]
+		parts = parts[1:]
+		if part == ".." {
+			if len(stack) == 1 {
+				return nil, errEscape
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		cur := stack[len(stack)-1]
+		if !cur.mode.IsDir() {
+			return nil, errNotDir
+		}
+		node, ok := cur.children[part]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		if node.mode.Type() == fs.ModeSymlink && (len(parts) > 0 || follow) {
+			if links++; links > maxLinks {
+				return nil, errLoop
+			}
+			dst := string(node.data)
+			if path.IsAbs(dst) {
+				return nil, errEscape
+			}
+			parts = append(splitPath(dst), parts...)
+			continue
+		}
+		stack = append(stack, node)
+	}
+	return stack[len(stack)-1], nil
+}
+
+// memNode represents a file, directory or symbolic link in [MemFS].
+type memNode struct {
+	mode     fs.FileMode         // File type and permissions.
+	data     []byte              // File content or symbolic link destination.
+	modTime  time.Time           // Modification time.
+	children map[string]*memNode // Directory entries, nil for other files.
+}
+
+// newDir returns a new directory node with the given permissions.
+func newDir(perm fs.FileMode, now time.Time) *memNode {
+	return &memNode{
+		mode:     fs.ModeDir | perm&fs.ModePerm,
+		modTime:  now,
+		children: make(map[string]*memNode),
+	}
+}
+
+// info returns a [fs.FileInfo] describing the node with the given name.
+func (node *memNode) info(name string) *memInfo {
+	return &memInfo{
+		name:    name,
+		size:    int64(len(node.data)),
+		mode:    node.mode,
+		modTime: node.modTime,
+	}
+}
+
+// entries returns directory entries sorted by name.
+func (node *memNode) entries() []fs.DirEntry {
+	entries := make([]fs.DirEntry, 0, len(node.children))
+	for _, name := range slices.Sorted(maps.Keys(node.children)) {
+		info := node.children[name].info(name)
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	return entries
+}
+
+// contains returns true if the directory is the node or one of its
+// descendants.
+func (node *memNode) contains(dir *memNode) bool {
+	if node == dir {
+		return true
+	}
+	for _, child := range node.children {
+		if child.mode.IsDir() && child.contains(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// memInfo is a [fs.FileInfo] of a [memNode].
+type memInfo struct {
+	name    string      // Base name of the file.
+	size    int64       // Length in bytes.
+	mode    fs.FileMode // File mode bits.
+	modTime time.Time   // Modification time.
+}
+
+func (inf *memInfo) Name() string       { return inf.name }
+func (inf *memInfo) Size() int64        { return inf.size }
+func (inf *memInfo) Mode() fs.FileMode  { return inf.mode }
+func (inf *memInfo) ModTime() time.Time { return inf.modTime }
+func (inf *memInfo) IsDir() bool        { return inf.mode.IsDir() }
+func (inf *memInfo) Sys() any           { return nil }
+
+// memFile is a file opened for reading by [MemFS.Open]. It reads the file
+// content as of the time it was opened.
+type memFile struct {
+	*bytes.Reader
+	info *memInfo // File information.
+}
+
+func (fil *memFile) Stat() (fs.FileInfo, error) { return fil.info, nil }
+func (fil *memFile) Close() error               { return nil }
+
+// memDir is a directory opened for reading by [MemFS.Open]. It lists the
+// directory entries as of the time it was opened.
+type memDir struct {
+	info    *memInfo      // Directory information.
+	entries []fs.DirEntry // Directory entries.
+	off     int           // Number of entries already read.
+}
+
+func (dir *memDir) Stat() (fs.FileInfo, error) { return dir.info, nil }
+func (dir *memDir) Close() error               { return nil }
+
+func (dir *memDir) Read([]byte) (int, error) {
+	return 0, pathError("read", dir.info.name, errIsDir)
+}
+
+// ReadDir reads directory entries, see [fs.ReadDirFile].
+func (dir *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := dir.entries[dir.off:]
+	if n <= 0 {
+		dir.off += len(rest)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	dir.off += n
+	return rest[:n], nil
+}
+
+// memWriter is a file opened for writing by [MemFS.Create]. Writes are
+// appended to the file and update its modification time.
+type memWriter struct {
+	mfs    *MemFS   // The filesystem.
+	node   *memNode // The file node.
+	name   string   // Name passed to [MemFS.Create].
+	closed bool     // True when the file was closed.
+}
+
+func (fil *memWriter) Stat() (fs.FileInfo, error) {
+	fil.mfs.mx.RLock()
+	defer fil.mfs.mx.RUnlock()
+	if fil.closed {
+		return nil, pathError("stat", fil.name, fs.ErrClosed)
+	}
+	return fil.node.info(path.Base(fil.name)), nil
+}
+
+func (fil *memWriter) Read([]byte) (int, error) {
+	return 0, pathError("read", fil.name, fs.ErrInvalid)
+}
+
+func (fil *memWriter) Write(p []byte) (int, error) {
+	now := fil.mfs.tk.Now()
+	fil.mfs.mx.Lock()
+	defer fil.mfs.mx.Unlock()
+	if fil.closed {
+		return 0, pathError("write", fil.name, fs.ErrClosed)
+	}
+	fil.node.data = append(fil.node.data, p...)
+	fil.node.modTime = now
+	return len(p), nil
+}
+
+func (fil *memWriter) Close() error {
+	fil.mfs.mx.Lock()
+	defer fil.mfs.mx.Unlock()
+	if fil.closed {
+		return pathError("close", fil.name, fs.ErrClosed)
+	}
+	fil.closed = true
+	return nil
+}
+
+// splitPath splits the slash-separated path into elements skipping empty
+// and "." elements.
+func splitPath(pth string) []string {
+	var parts []string
+	for part := range strings.SplitSeq(pth, "/") {
+		if part != "" && part != "." {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// pathError returns a [fs.PathError] with the given values.
+func pathError(op, name string, err error) error {
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+// linkError returns a [os.LinkError] with the given values.
+func linkError(op, oldname, newname string, err error) error {
+	return &os.LinkError{Op: op, Old: oldname, New: newname, Err: err}
+}
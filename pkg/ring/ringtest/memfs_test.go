@@ -0,0 +1,1493 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ringtest
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+	"github.com/ctx42/testing/pkg/tester"
+
+	"github.com/ctx42/ring/pkg/ring"
+)
+
+// memTree returns a [MemFS] with a few files, directories and links using
+// a [Clock] set to clockStart.
+func memTree() (*MemFS, *Clock) {
+	clk := NewClock(clockStart)
+	mfs := NewMemFS(clk)
+	must.Nil(mfs.MkdirAll("a/b", 0o755))
+	must.Nil(mfs.WriteFile("a/f.txt", []byte("abc"), 0o644))
+	must.Nil(mfs.WriteFile("a/b/g.txt", []byte("xyz"), 0o600))
+	must.Nil(mfs.Symlink("a/f.txt", "link"))
+	must.Nil(mfs.Symlink("..", "a/b/up"))
+	return mfs, clk
+}
+
+func Test_NewMemFS(t *testing.T) {
+	t.Run("clock", func(t *testing.T) {
+		// --- Given ---
+		clk := NewClock(clockStart)
+
+		// --- When ---
+		have := NewMemFS(clk)
+
+		// --- Then ---
+		assert.Same(t, clk, have.tk)
+		info := must.Value(have.Stat("."))
+		assert.True(t, info.IsDir())
+		assert.Equal(t, fs.ModeDir|0o755, info.Mode())
+		assert.Equal(t, clockStart, info.ModTime())
+		assert.Nil(t, have.Tree())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- When ---
+		have := NewMemFS(nil)
+
+		// --- Then ---
+		assert.Same(t, ring.NowUTC, have.tk.(ring.Clock))
+	})
+}
+
+func Test_MemFS_fstest(t *testing.T) {
+	// --- Given ---
+	mfs, _ := memTree()
+
+	// --- When ---
+	err := fstest.TestFS(mfs, "a/f.txt", "a/b/g.txt", "link", "a/b/up")
+
+	// --- Then ---
+	assert.NoError(t, err)
+}
+
+func Test_MemFS_Open(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		fil, err := mfs.Open("a/f.txt")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", string(must.Value(io.ReadAll(fil))))
+		info := must.Value(fil.Stat())
+		assert.Equal(t, "f.txt", info.Name())
+		assert.Equal(t, int64(3), info.Size())
+		assert.NoError(t, fil.Close())
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		fil, err := mfs.Open("a")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		dir := fil.(fs.ReadDirFile)
+		have := must.Value(dir.ReadDir(1))
+		assert.Equal(t, "b", have[0].Name())
+		have = must.Value(dir.ReadDir(-1))
+		assert.Equal(t, "f.txt", have[0].Name())
+		_, err = dir.ReadDir(1)
+		assert.ErrorIs(t, io.EOF, err)
+		_, err = dir.Read(make([]byte, 1))
+		assert.ErrorEqual(t, "read a: is a directory", err)
+	})
+
+	t.Run("content as of opening", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		fil := must.Value(mfs.Open("a/f.txt"))
+		must.Nil(mfs.WriteFile("a/f.txt", []byte("def"), 0o644))
+
+		// --- When ---
+		have, err := io.ReadAll(fil)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", string(have))
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		fil, err := mfs.Open("/a")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+		assert.ErrorEqual(t, "open /a: invalid argument", err)
+		assert.Nil(t, fil)
+	})
+
+	t.Run("error - not existing", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		fil, err := mfs.Open("a/x.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+		assert.ErrorEqual(t, "open a/x.txt: file does not exist", err)
+		assert.Nil(t, fil)
+	})
+
+	t.Run("error - no read permission", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Chmod("a/f.txt", 0o200))
+
+		// --- When ---
+		fil, err := mfs.Open("a/f.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrPermission, err)
+		assert.Nil(t, fil)
+	})
+}
+
+func Test_MemFS_Stat(t *testing.T) {
+	t.Run("follows links", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.Stat("link")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "link", have.Name())
+		assert.Equal(t, fs.FileMode(0o644), have.Mode())
+		assert.Equal(t, int64(3), have.Size())
+		assert.Nil(t, have.Sys())
+	})
+
+	t.Run("link in path", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.Stat("a/b/up/b/g.txt")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "g.txt", have.Name())
+	})
+
+	t.Run("error - not a directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.Stat("a/f.txt/x")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "stat a/f.txt/x: not a directory", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - link escapes root", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Symlink("../x", "out"))
+
+		// --- When ---
+		have, err := mfs.Stat("out")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "stat out: path escapes from root", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - absolute link", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Symlink("/etc/passwd", "abs"))
+
+		// --- When ---
+		have, err := mfs.Stat("abs")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "stat abs: path escapes from root", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - link loop", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Symlink("l2", "l1"))
+		must.Nil(mfs.Symlink("l1", "l2"))
+
+		// --- When ---
+		have, err := mfs.Stat("l1")
+
+		// --- Then ---
+		wMsg := "stat l1: too many levels of symbolic links"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_MemFS_Lstat(t *testing.T) {
+	t.Run("link", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.Lstat("link")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, fs.ModeSymlink|0o777, have.Mode())
+		assert.Equal(t, int64(7), have.Size())
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.Lstat("a/")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "lstat a/: invalid argument", err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_MemFS_ReadFile(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.ReadFile("link")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", string(have))
+	})
+
+	t.Run("returns a copy", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		data := must.Value(mfs.ReadFile("a/f.txt"))
+
+		// --- When ---
+		data[0] = 'X'
+
+		// --- Then ---
+		mfs.AssertFile(t, "a/f.txt", "abc")
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.ReadFile("../a")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - not existing", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.ReadFile("x")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.ReadFile("a")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "readfile a: is a directory", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - no read permission", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Chmod("a/f.txt", 0o200))
+
+		// --- When ---
+		have, err := mfs.ReadFile("a/f.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrPermission, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_MemFS_ReadDir(t *testing.T) {
+	t.Run("directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.ReadDir(".")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Len(t, 2, have)
+		assert.Equal(t, "a", have[0].Name())
+		assert.True(t, have[0].IsDir())
+		assert.Equal(t, "link", have[1].Name())
+		assert.Equal(t, fs.ModeSymlink, have[1].Type())
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.ReadDir("")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - not existing", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.ReadDir("x")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - not a directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.ReadDir("a/f.txt")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "readdir a/f.txt: not a directory", err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - no read permission", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Chmod("a", 0o300))
+
+		// --- When ---
+		have, err := mfs.ReadDir("a")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrPermission, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_MemFS_ReadLink(t *testing.T) {
+	t.Run("link", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.ReadLink("a/b/up")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "..", have)
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.ReadLink("/link")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+		assert.Empty(t, have)
+	})
+
+	t.Run("error - not existing", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.ReadLink("x")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+		assert.Empty(t, have)
+	})
+
+	t.Run("error - not a link", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have, err := mfs.ReadLink("a/f.txt")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "readlink a/f.txt: not a symbolic link", err)
+		assert.Empty(t, have)
+	})
+}
+
+func Test_MemFS_Create(t *testing.T) {
+	t.Run("new file", func(t *testing.T) {
+		// --- Given ---
+		mfs, clk := memTree()
+		clk.Advance(time.Hour)
+
+		// --- When ---
+		fil, err := mfs.Create("a/new.txt")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		clk.Advance(time.Minute)
+		must.Value(fil.Write([]byte("abc")))
+		must.Value(fil.Write([]byte("def")))
+		assert.NoError(t, fil.Close())
+
+		mfs.AssertFile(t, "a/new.txt", "abcdef")
+		info := must.Value(mfs.Stat("a/new.txt"))
+		assert.Equal(t, fs.FileMode(0o666), info.Mode())
+		assert.Equal(t, clockStart.Add(time.Hour+time.Minute), info.ModTime())
+		dir := must.Value(mfs.Stat("a"))
+		assert.Equal(t, clockStart.Add(time.Hour), dir.ModTime())
+	})
+
+	t.Run("truncates existing", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		fil, err := mfs.Create("link")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.NoError(t, fil.Close())
+		mfs.AssertFile(t, "a/f.txt", "")
+		info := must.Value(mfs.Stat("a/f.txt"))
+		assert.Equal(t, fs.FileMode(0o644), info.Mode())
+	})
+
+	t.Run("file", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		fil := must.Value(mfs.Create("new.txt"))
+		must.Value(fil.Write([]byte("abc")))
+
+		// --- When ---
+		info, err := fil.Stat()
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "new.txt", info.Name())
+		assert.Equal(t, int64(3), info.Size())
+		_, err = fil.Read(make([]byte, 1))
+		assert.ErrorEqual(t, "read new.txt: invalid argument", err)
+	})
+
+	t.Run("closed file", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		fil := must.Value(mfs.Create("new.txt"))
+		must.Nil(fil.Close())
+
+		// --- When ---
+		n, err := fil.Write([]byte("abc"))
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrClosed, err)
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, fs.ErrClosed, fil.Close())
+		_, err = fil.Stat()
+		assert.ErrorIs(t, fs.ErrClosed, err)
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		fil, err := mfs.Create("a/../f.txt")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "create a/../f.txt: invalid argument", err)
+		assert.Nil(t, fil)
+	})
+
+	t.Run("error - directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		fil, err := mfs.Create("a")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "create a: is a directory", err)
+		assert.Nil(t, fil)
+	})
+
+	t.Run("error - missing directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		fil, err := mfs.Create("x/f.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+		assert.Nil(t, fil)
+	})
+
+	t.Run("error - parent not a directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		fil, err := mfs.Create("a/f.txt/x")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "create a/f.txt/x: not a directory", err)
+		assert.Nil(t, fil)
+	})
+
+	t.Run("error - no file write permission", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Chmod("a/f.txt", 0o444))
+
+		// --- When ---
+		fil, err := mfs.Create("a/f.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrPermission, err)
+		assert.Nil(t, fil)
+		mfs.AssertFile(t, "a/f.txt", "abc")
+	})
+
+	t.Run("error - no directory write permission", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Chmod("a", 0o555))
+
+		// --- When ---
+		fil, err := mfs.Create("a/new.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrPermission, err)
+		assert.Nil(t, fil)
+		mfs.AssertNotExist(t, "a/new.txt")
+	})
+}
+
+func Test_MemFS_WriteFile(t *testing.T) {
+	t.Run("new file", func(t *testing.T) {
+		// --- Given ---
+		mfs, clk := memTree()
+		clk.Advance(time.Hour)
+
+		// --- When ---
+		err := mfs.WriteFile("a/new.txt", []byte("abc"), 0o640|fs.ModeDir)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		mfs.AssertFile(t, "a/new.txt", "abc")
+		info := must.Value(mfs.Stat("a/new.txt"))
+		assert.Equal(t, fs.FileMode(0o640), info.Mode())
+		assert.Equal(t, clockStart.Add(time.Hour), info.ModTime())
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		// --- Given ---
+		mfs, clk := memTree()
+		clk.Advance(time.Hour)
+
+		// --- When ---
+		err := mfs.WriteFile("a/f.txt", []byte("de"), 0o600)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		mfs.AssertFile(t, "a/f.txt", "de")
+		info := must.Value(mfs.Stat("a/f.txt"))
+		assert.Equal(t, fs.FileMode(0o644), info.Mode())
+		assert.Equal(t, clockStart.Add(time.Hour), info.ModTime())
+	})
+
+	t.Run("data is copied", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		data := []byte("abc")
+
+		// --- When ---
+		err := mfs.WriteFile("new.txt", data, 0o600)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		data[0] = 'X'
+		mfs.AssertFile(t, "new.txt", "abc")
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.WriteFile(".", nil, 0o600)
+
+		// --- Then ---
+		assert.ErrorEqual(t, "writefile .: is a directory", err)
+	})
+
+	t.Run("error - link escapes root", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Symlink("../x", "out"))
+
+		// --- When ---
+		err := mfs.WriteFile("out/f.txt", nil, 0o600)
+
+		// --- Then ---
+		assert.ErrorEqual(t, "writefile out/f.txt: path escapes from root", err)
+	})
+}
+
+func Test_MemFS_Mkdir(t *testing.T) {
+	t.Run("directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, clk := memTree()
+		clk.Advance(time.Hour)
+
+		// --- When ---
+		err := mfs.Mkdir("a/c", 0o700)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		info := must.Value(mfs.Stat("a/c"))
+		assert.Equal(t, fs.ModeDir|0o700, info.Mode())
+		assert.Equal(t, clockStart.Add(time.Hour), info.ModTime())
+		dir := must.Value(mfs.Stat("a"))
+		assert.Equal(t, clockStart.Add(time.Hour), dir.ModTime())
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Mkdir("a//c", 0o700)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+	})
+
+	t.Run("error - exists", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Mkdir("a/f.txt", 0o700)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrExist, err)
+		assert.ErrorEqual(t, "mkdir a/f.txt: file already exists", err)
+	})
+
+	t.Run("error - root", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Mkdir(".", 0o700)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrExist, err)
+	})
+
+	t.Run("error - missing parent", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Mkdir("x/y", 0o700)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+	})
+}
+
+func Test_MemFS_MkdirAll(t *testing.T) {
+	t.Run("directories", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.MkdirAll("a/b/up/c/d", 0o700)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		mfs.AssertExist(t, "a/c/d")
+		info := must.Value(mfs.Stat("a/c"))
+		assert.Equal(t, fs.ModeDir|0o700, info.Mode())
+	})
+
+	t.Run("existing", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.MkdirAll("a/b", 0o700)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		info := must.Value(mfs.Stat("a/b"))
+		assert.Equal(t, fs.ModeDir|0o755, info.Mode())
+	})
+
+	t.Run("root", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.MkdirAll(".", 0o700)
+
+		// --- Then ---
+		assert.NoError(t, err)
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.MkdirAll("a/../..", 0o700)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+	})
+
+	t.Run("error - not a directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.MkdirAll("a/f.txt/c", 0o700)
+
+		// --- Then ---
+		assert.ErrorEqual(t, "mkdirall a/f.txt: not a directory", err)
+	})
+
+	t.Run("error - link escapes root", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Symlink("../x", "out"))
+
+		// --- When ---
+		err := mfs.MkdirAll("out/c", 0o700)
+
+		// --- Then ---
+		assert.ErrorEqual(t, "mkdirall out: path escapes from root", err)
+	})
+
+	t.Run("error - no write permission", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Chmod("a", 0o500))
+
+		// --- When ---
+		err := mfs.MkdirAll("a/c/d", 0o700)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrPermission, err)
+		assert.ErrorEqual(t, "mkdirall a/c: permission denied", err)
+	})
+}
+
+func Test_MemFS_Remove(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		// --- Given ---
+		mfs, clk := memTree()
+		clk.Advance(time.Hour)
+
+		// --- When ---
+		err := mfs.Remove("a/f.txt")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		mfs.AssertNotExist(t, "a/f.txt")
+		dir := must.Value(mfs.Stat("a"))
+		assert.Equal(t, clockStart.Add(time.Hour), dir.ModTime())
+	})
+
+	t.Run("link", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Remove("link")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		mfs.AssertNotExist(t, "link")
+		mfs.AssertExist(t, "a/f.txt")
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Mkdir("c", 0o700))
+
+		// --- When ---
+		err := mfs.Remove("c")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		mfs.AssertNotExist(t, "c")
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Remove(".")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "remove .: invalid argument", err)
+	})
+
+	t.Run("error - not existing", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Remove("a/x")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+	})
+
+	t.Run("error - missing parent", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Remove("x/y")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+	})
+
+	t.Run("error - not empty directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Remove("a")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "remove a: directory not empty", err)
+	})
+
+	t.Run("error - no write permission", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Chmod("a", 0o500))
+
+		// --- When ---
+		err := mfs.Remove("a/f.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrPermission, err)
+		mfs.AssertExist(t, "a/f.txt")
+	})
+}
+
+func Test_MemFS_RemoveAll(t *testing.T) {
+	t.Run("directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.RemoveAll("a")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"link -> a/f.txt"}, mfs.Tree())
+	})
+
+	t.Run("not existing", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.RemoveAll("a/x")
+
+		// --- Then ---
+		assert.NoError(t, err)
+	})
+
+	t.Run("missing parent", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.RemoveAll("x/y")
+
+		// --- Then ---
+		assert.NoError(t, err)
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.RemoveAll(".")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "removeall .: invalid argument", err)
+	})
+
+	t.Run("error - parent not a directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.RemoveAll("a/f.txt/x")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "removeall a/f.txt/x: not a directory", err)
+	})
+
+	t.Run("error - no write permission", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Chmod(".", 0o500))
+
+		// --- When ---
+		err := mfs.RemoveAll("a")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrPermission, err)
+		mfs.AssertExist(t, "a")
+	})
+}
+
+func Test_MemFS_Rename(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		// --- Given ---
+		mfs, clk := memTree()
+		clk.Advance(time.Hour)
+
+		// --- When ---
+		err := mfs.Rename("a/f.txt", "a/b/h.txt")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		mfs.AssertNotExist(t, "a/f.txt")
+		mfs.AssertFile(t, "a/b/h.txt", "abc")
+		info := must.Value(mfs.Stat("a/b/h.txt"))
+		assert.Equal(t, clockStart, info.ModTime())
+		dir := must.Value(mfs.Stat("a/b"))
+		assert.Equal(t, clockStart.Add(time.Hour), dir.ModTime())
+	})
+
+	t.Run("replace file", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Rename("a/f.txt", "a/b/g.txt")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		mfs.AssertFile(t, "a/b/g.txt", "abc")
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Rename("a/b", "c")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := []string{
+			"a/",
+			"a/f.txt",
+			"c/",
+			"c/g.txt",
+			"c/up -> ..",
+			"link -> a/f.txt",
+		}
+		assert.Equal(t, want, mfs.Tree())
+	})
+
+	t.Run("replace empty directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Mkdir("c", 0o700))
+
+		// --- When ---
+		err := mfs.Rename("a/b", "c")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		mfs.AssertFile(t, "c/g.txt", "xyz")
+	})
+
+	t.Run("same name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Rename("a/f.txt", "a/f.txt")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		mfs.AssertFile(t, "a/f.txt", "abc")
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Rename("a/f.txt", ".")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "rename a/f.txt .: invalid argument", err)
+	})
+
+	t.Run("error - not existing", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Rename("a/x", "y")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+		var e *os.LinkError
+		assert.True(t, errors.As(err, &e))
+	})
+
+	t.Run("error - missing source parent", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Rename("x/y", "z")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+	})
+
+	t.Run("error - missing destination parent", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Rename("a/f.txt", "x/y")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+		mfs.AssertExist(t, "a/f.txt")
+	})
+
+	t.Run("error - directory into itself", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Rename("a", "a/b/c")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "rename a a/b/c: invalid argument", err)
+	})
+
+	t.Run("error - file over directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Rename("a/f.txt", "a/b")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "rename a/f.txt a/b: is a directory", err)
+	})
+
+	t.Run("error - directory over file", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Rename("a/b", "a/f.txt")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "rename a/b a/f.txt: not a directory", err)
+	})
+
+	t.Run("error - not empty directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Mkdir("c", 0o700))
+
+		// --- When ---
+		err := mfs.Rename("c", "a")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "rename c a: directory not empty", err)
+	})
+
+	t.Run("error - no write permission", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+		must.Nil(mfs.Chmod("a/b", 0o500))
+
+		// --- When ---
+		err := mfs.Rename("a/f.txt", "a/b/h.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrPermission, err)
+		mfs.AssertExist(t, "a/f.txt")
+	})
+}
+
+func Test_MemFS_Chmod(t *testing.T) {
+	t.Run("file", func(t *testing.T) {
+		// --- Given ---
+		mfs, clk := memTree()
+		clk.Advance(time.Hour)
+
+		// --- When ---
+		err := mfs.Chmod("link", 0o400|fs.ModeDir)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		info := must.Value(mfs.Stat("a/f.txt"))
+		assert.Equal(t, fs.FileMode(0o400), info.Mode())
+		assert.Equal(t, clockStart, info.ModTime())
+		link := must.Value(mfs.Lstat("link"))
+		assert.Equal(t, fs.ModeSymlink|0o777, link.Mode())
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Chmod("a", 0o700)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		info := must.Value(mfs.Stat("a"))
+		assert.Equal(t, fs.ModeDir|0o700, info.Mode())
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Chmod("./a", 0o700)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+	})
+
+	t.Run("error - not existing", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Chmod("x", 0o700)
+
+		// --- Then ---
+		assert.ErrorEqual(t, "chmod x: file does not exist", err)
+	})
+}
+
+func Test_MemFS_Symlink(t *testing.T) {
+	t.Run("link", func(t *testing.T) {
+		// --- Given ---
+		mfs, clk := memTree()
+		clk.Advance(time.Hour)
+
+		// --- When ---
+		err := mfs.Symlink("../b/g.txt", "a/b/l")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		mfs.AssertFile(t, "a/b/l", "xyz")
+		info := must.Value(mfs.Lstat("a/b/l"))
+		assert.Equal(t, clockStart.Add(time.Hour), info.ModTime())
+	})
+
+	t.Run("dangling link", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Symlink("x", "l")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		mfs.AssertExist(t, "l")
+		_, err = mfs.Stat("l")
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Symlink("a", "../l")
+
+		// --- Then ---
+		assert.ErrorEqual(t, "symlink a ../l: invalid argument", err)
+	})
+
+	t.Run("error - exists", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		err := mfs.Symlink("a", "link")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrExist, err)
+		assert.Equal(t, "a/f.txt", must.Value(mfs.ReadLink("link")))
+	})
+}
+
+func Test_MemFS_Tree(t *testing.T) {
+	t.Run("tree", func(t *testing.T) {
+		// --- Given ---
+		mfs, _ := memTree()
+
+		// --- When ---
+		have := mfs.Tree()
+
+		// --- Then ---
+		want := []string{
+			"a/",
+			"a/b/",
+			"a/b/g.txt",
+			"a/b/up -> ..",
+			"a/f.txt",
+			"link -> a/f.txt",
+		}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		// --- Given ---
+		mfs := NewMemFS(nil)
+
+		// --- When ---
+		have := mfs.Tree()
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_MemFS_AssertExist(t *testing.T) {
+	t.Run("exists", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.Close()
+
+		mfs, _ := memTree()
+		must.Nil(mfs.Symlink("x", "dangling"))
+
+		// --- When ---
+		have := mfs.AssertExist(tspy, "dangling")
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("error - not existing", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectError()
+		tspy.IgnoreLogs()
+		tspy.Close()
+
+		mfs, _ := memTree()
+
+		// --- When ---
+		have := mfs.AssertExist(tspy, "x")
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+}
+
+func Test_MemFS_AssertNotExist(t *testing.T) {
+	t.Run("not existing", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.Close()
+
+		mfs, _ := memTree()
+
+		// --- When ---
+		have := mfs.AssertNotExist(tspy, "x")
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("error - exists", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectError()
+		tspy.IgnoreLogs()
+		tspy.Close()
+
+		mfs, _ := memTree()
+
+		// --- When ---
+		have := mfs.AssertNotExist(tspy, "link")
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+}
+
+func Test_MemFS_AssertFile(t *testing.T) {
+	t.Run("content", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.Close()
+
+		mfs, _ := memTree()
+
+		// --- When ---
+		have := mfs.AssertFile(tspy, "a/f.txt", "abc")
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("error - different content", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectError()
+		tspy.IgnoreLogs()
+		tspy.Close()
+
+		mfs, _ := memTree()
+
+		// --- When ---
+		have := mfs.AssertFile(tspy, "a/f.txt", "xyz")
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+
+	t.Run("error - not existing", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectError()
+		tspy.IgnoreLogs()
+		tspy.Close()
+
+		mfs, _ := memTree()
+
+		// --- When ---
+		have := mfs.AssertFile(tspy, "x", "")
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+}
+
+func Test_MemFS_concurrent(t *testing.T) {
+	// --- Given ---
+	mfs := NewMemFS(nil)
+	must.Nil(mfs.Mkdir("d", 0o755))
+
+	// --- When ---
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			name := "d/" + string(rune('a'+i))
+			fil := must.Value(mfs.Create(name))
+			for range 100 {
+				must.Value(fil.Write([]byte("x")))
+				_, _ = mfs.ReadDir("d")
+				_, _ = mfs.ReadFile(name)
+			}
+			must.Nil(fil.Close())
+		})
+	}
+	wg.Wait()
+
+	// --- Then ---
+	entries := must.Value(mfs.ReadDir("d"))
+	assert.Len(t, 10, entries)
+	for _, entry := range entries {
+		info := must.Value(entry.Info())
+		assert.Equal(t, int64(100), info.Size())
+	}
+}
+
+func Test_MemFS_with_Tester(t *testing.T) {
+	// --- Given ---
+	tspy := tester.New(t)
+	tspy.ExpectCleanups(2)
+	tspy.Close()
+
+	clk := NewClock(clockStart)
+	mfs := NewMemFS(clk)
+	tst := New(tspy, ring.WithTimekeeper(clk), ring.WithWritableFS(mfs))
+	rng := tst.Ring()
+
+	// --- When ---
+	wfs := must.Value(rng.WritableFS())
+	must.Nil(wfs.MkdirAll("out", 0o755))
+	must.Nil(wfs.WriteFile("out/report.txt", []byte("ok\n"), 0o644))
+
+	// --- Then ---
+	mfs.AssertFile(t, "out/report.txt", "ok\n")
+	info := must.Value(mfs.Stat("out/report.txt"))
+	assert.Equal(t, clockStart, info.ModTime())
+}
@@ -6,7 +6,9 @@ package ringtest
 import (
 	"bytes"
 	"maps"
+	"slices"
 
+	"github.com/ctx42/testing/pkg/assert"
 	"github.com/ctx42/testing/pkg/kit/iokit"
 	"github.com/ctx42/testing/pkg/tester"
 
@@ -15,11 +17,14 @@ import (
 
 // Tester represents CLI test helper.
 type Tester struct {
-	rng  *ring.Ring    // The test ring.
-	sin  *bytes.Buffer // Buffer representing standard input.
-	sout *iokit.Buffer // Buffer to collect stdout writes.
-	eout *iokit.Buffer // Buffer to collect stderr writes.
-	t    tester.T      // The test manager.
+	rng   *ring.Ring     // The test ring.
+	audit *ring.EnvAudit // Environment audit of the last returned ring.
+	base  *ring.Env      // Environment snapshot of the last returned ring.
+	last  *ring.Ring     // The last returned ring.
+	sin   *bytes.Buffer  // Buffer representing standard input.
+	sout  *iokit.Buffer  // Buffer to collect stdout writes.
+	eout  *iokit.Buffer  // Buffer to collect stderr writes.
+	t     tester.T       // The test manager.
 }
 
 // New returns new instance of [Tester] with given options. By default, the
@@ -30,6 +35,7 @@ type Tester struct {
 //   - environment set to [os.Environ],
 //   - metadata set to an empty map,
 //   - clock set to [ring.NowUTC],
+//   - no filesystem access,
 //   - standard input set to empty [bytes.Buffer],
 //   - standard output set to [iokit.DryBuffer],
 //   - standard error set to [iokit.DryBuffer],
@@ -53,21 +59,115 @@ func New(t tester.T, opts ...ring.Option) *Tester {
 }
 
 // Ring returns a command environment based on [Tester] fields.
+//
+// The returned ring environment is a layer on top of an audited copy of the
+// [Tester] environment. It keeps case-insensitivity, sensitive variable
+// patterns and the frozen state of the [Tester] environment. The returned
+// ring is concurrent when the [Tester] ring is, see [ring.WithConcurrency]. Use
+// [Tester.EnvAudit] and [Tester.AssertEnvRead] to inspect which variables
+// the command read, and [Tester.EnvDiff], [Tester.AssertEnvSet] and
+// [Tester.AssertEnvUnset] to inspect how the command modified its
+// environment.
 func (tst *Tester) Ring(args ...string) *ring.Ring {
+	tst.base = tst.rng.EnvFlatten()
+	tst.audit = ring.NewEnvAudit(tst.base.EnvClone())
+	fsys, _ := tst.rng.FS()
+	wfs, _ := tst.rng.WritableFS()
+	envOpt := ring.WithEnvLayer(tst.audit)
+	if tst.rng.EnvFrozen() {
+		envOpt = ring.WithEnvFrozen(tst.audit)
+	}
 	opts := []ring.Option{
-		ring.WithEnv(tst.rng.EnvAll()),
+		envOpt,
 		ring.WithMeta(maps.Clone(tst.rng.MetaAll())),
-		ring.WithClock(tst.rng.Clock()),
+		ring.WithTimekeeper(tst.rng.Timekeeper()),
+		ring.WithLocation(tst.rng.Location()),
+		ring.WithFS(fsys),
+		ring.WithWritableFS(wfs),
 		ring.WithName(tst.rng.Name()),
 		ring.WithArgs(args),
 	}
+	if tst.rng.Concurrent() {
+		opts = append(opts, ring.WithConcurrency())
+	}
 	rng := ring.New(opts...)
 	rng.SetStdin(tst.sin)
 	rng.SetStdout(tst.sout)
 	rng.SetStderr(tst.eout)
+	tst.last = rng
 	return rng
 }
 
+// EnvAudit returns the environment audit of the last ring returned by
+// [Tester.Ring]. It records reads of variables provided by the [Tester]
+// environment, variables set by the command itself are not recorded. Returns
+// nil if [Tester.Ring] was not called.
+func (tst *Tester) EnvAudit() *ring.EnvAudit { return tst.audit }
+
+// AssertEnvRead asserts the command, run with the last ring returned by
+// [Tester.Ring], read exactly the given environment variables.
+func (tst *Tester) AssertEnvRead(keys ...string) bool {
+	tst.t.Helper()
+	var want, have []string
+	if len(keys) > 0 {
+		want = slices.Sorted(slices.Values(keys))
+	}
+	if tst.audit != nil {
+		have = tst.audit.Read()
+	}
+	return assert.Equal(tst.t, want, have)
+}
+
+// EnvDiff returns changes the command made to the environment of the last
+// ring returned by [Tester.Ring]. Returns an empty diff if [Tester.Ring] was
+// not called.
+func (tst *Tester) EnvDiff() ring.EnvDiff {
+	if tst.last == nil {
+		return ring.EnvDiff{}
+	}
+	cur := tst.base.EnvClone()
+	set, unset := tst.last.EnvLayer()
+	cur.EnvSetFrom(set)
+	for _, key := range unset {
+		cur.EnvUnset(key)
+	}
+	return ring.DiffEnv(tst.base, cur)
+}
+
+// AssertEnvSet asserts the command, run with the last ring returned by
+// [Tester.Ring], set exactly the given environment variables to the given
+// values. Only variables added or changed by the command are considered,
+// setting a variable to its current value is not a change.
+func (tst *Tester) AssertEnvSet(want map[string]string) bool {
+	tst.t.Helper()
+	diff := tst.EnvDiff()
+	have := maps.Clone(diff.Added)
+	for key, chg := range diff.Changed {
+		have[key] = chg.New
+	}
+	if len(want) == 0 {
+		want = nil
+	}
+	if len(have) == 0 {
+		have = nil
+	}
+	return assert.Equal(tst.t, want, have)
+}
+
+// AssertEnvUnset asserts the command, run with the last ring returned by
+// [Tester.Ring], unset exactly the given environment variables.
+func (tst *Tester) AssertEnvUnset(keys ...string) bool {
+	tst.t.Helper()
+	var want, have []string
+	if len(keys) > 0 {
+		want = slices.Sorted(slices.Values(keys))
+	}
+	if diff := tst.EnvDiff(); len(diff.Removed) > 0 {
+		have = slices.Sorted(maps.Keys(diff.Removed))
+	}
+	return assert.Equal(tst.t, want, have)
+}
+
 // Streams returns standard streams based on [Tester] fields.
 func (tst *Tester) Streams() *ring.IO {
 	ios := ring.NewIO()
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
 	"github.com/ctx42/testing/pkg/tester"
 
 	"github.com/ctx42/ring/pkg/ring"
@@ -137,6 +138,432 @@ func Test_Tester_Ring(t *testing.T) {
 		// --- Then ---
 		assert.NotSame(t, m, rng.MetaAll())
 	})
+
+	t.Run("remembers the last ring", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A=1"}))
+
+		// --- When ---
+		rng := tst.Ring()
+
+		// --- Then ---
+		assert.Same(t, rng, tst.last)
+		assert.Equal(t, []string{"A=1"}, tst.base.EnvAll())
+	})
+
+	t.Run("with filesystems", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		mfs := NewMemFS(nil)
+		tst := New(tspy, ring.WithFS(mfs), ring.WithWritableFS(mfs))
+
+		// --- When ---
+		rng := tst.Ring()
+
+		// --- Then ---
+		assert.Same(t, mfs, must.Value(rng.FS()))
+		assert.Same(t, mfs, must.Value(rng.WritableFS()))
+	})
+
+	t.Run("no filesystem access", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy)
+
+		// --- When ---
+		rng := tst.Ring()
+
+		// --- Then ---
+		_, err := rng.FS()
+		assert.ErrorIs(t, ring.ErrNoFsAccess, err)
+		_, err = rng.WritableFS()
+		assert.ErrorIs(t, ring.ErrNoFsAccess, err)
+	})
+
+	t.Run("case-insensitive environment", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		env := []string{"Path=/bin"}
+		tst := New(tspy, ring.WithEnv(env, ring.WithCaseInsensitive()))
+
+		// --- When ---
+		rng := tst.Ring()
+
+		// --- Then ---
+		assert.True(t, rng.EnvCaseInsensitive())
+		assert.Equal(t, "/bin", rng.EnvGet("PATH"))
+		rng.EnvSet("PATH", "/usr/bin")
+		assert.Equal(t, []string{"Path=/usr/bin"}, rng.EnvAll())
+	})
+
+	t.Run("sensitive variables", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A_TOKEN=abc", "B=2"}))
+		tst.rng.EnvSensitive("*_TOKEN")
+
+		// --- When ---
+		rng := tst.Ring()
+
+		// --- Then ---
+		assert.True(t, rng.EnvIsSensitive("A_TOKEN"))
+		assert.False(t, rng.EnvIsSensitive("B"))
+		want := []string{"A_TOKEN=" + ring.Redacted, "B=2"}
+		assert.Equal(t, want, rng.EnvRedacted())
+	})
+
+	t.Run("frozen environment", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnvFrozen(ring.NewEnv([]string{"A=1"})))
+
+		// --- When ---
+		rng := tst.Ring()
+
+		// --- Then ---
+		assert.True(t, rng.EnvFrozen())
+		assert.Equal(t, "1", rng.EnvGet("A"))
+		assert.Panic(t, func() { rng.EnvSet("A", "2") })
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithConcurrency())
+
+		// --- When ---
+		rng := tst.Ring()
+
+		// --- Then ---
+		assert.True(t, rng.Concurrent())
+	})
+
+	t.Run("not concurrent", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy)
+
+		// --- When ---
+		rng := tst.Ring()
+
+		// --- Then ---
+		assert.False(t, rng.Concurrent())
+	})
+}
+
+func Test_Tester_EnvAudit(t *testing.T) {
+	t.Run("ring not created", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy)
+
+		// --- When ---
+		have := tst.EnvAudit()
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+
+	t.Run("records reads of the last ring", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A=1", "B=2", "C=3"}))
+		_ = tst.Ring().EnvGet("C")
+		rng := tst.Ring()
+
+		// --- When ---
+		_ = rng.EnvGet("A")
+		_, _ = rng.EnvLookup("X")
+		rng.EnvSet("D", "4")
+		_ = rng.EnvGet("D")
+
+		// --- Then ---
+		have := tst.EnvAudit()
+		assert.Equal(t, []string{"A", "X"}, have.Read())
+		assert.Equal(t, []string{"X"}, have.Missing())
+		assert.Equal(t, []string{"B", "C"}, have.Unused())
+		assert.Equal(t, []string{"A=1", "B=2", "C=3"}, Sort(tst.rng.EnvAll()))
+	})
+}
+
+func Test_Tester_AssertEnvRead(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A=1", "B=2"}))
+		rng := tst.Ring()
+		_ = rng.EnvGet("B")
+		_ = rng.EnvGet("A")
+
+		// --- When ---
+		have := tst.AssertEnvRead("A", "B")
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("success - nothing read", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A=1"}))
+		_ = tst.Ring()
+
+		// --- When ---
+		have := tst.AssertEnvRead()
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("success - ring not created", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy)
+
+		// --- When ---
+		have := tst.AssertEnvRead()
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("error - different variables read", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.ExpectError()
+		tspy.IgnoreLogs()
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A=1", "B=2"}))
+		_ = tst.Ring().EnvGet("A")
+
+		// --- When ---
+		have := tst.AssertEnvRead("B")
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+}
+
+func Test_Tester_EnvDiff(t *testing.T) {
+	t.Run("changes", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A=1", "B=2", "C=3"}))
+		rng := tst.Ring()
+		rng.EnvSet("A", "-1")
+		rng.EnvUnset("B")
+		rng.EnvSet("C", "3")
+		rng.EnvSet("D", "4")
+
+		// --- When ---
+		have := tst.EnvDiff()
+
+		// --- Then ---
+		assert.Equal(t, "~A=1 -> -1\n-B=2\n+D=4\n", have.String())
+	})
+
+	t.Run("does not record accesses", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A=1"}))
+		_ = tst.Ring()
+
+		// --- When ---
+		_ = tst.EnvDiff()
+
+		// --- Then ---
+		assert.Nil(t, tst.EnvAudit().Accesses())
+	})
+
+	t.Run("sensitive values are redacted", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv(nil))
+		tst.rng.EnvSensitive("TOKEN")
+		tst.Ring().EnvSet("TOKEN", "secret")
+
+		// --- When ---
+		have := tst.EnvDiff()
+
+		// --- Then ---
+		assert.Equal(t, "+TOKEN=[REDACTED]\n", have.String())
+	})
+
+	t.Run("ring not created", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy)
+
+		// --- When ---
+		have := tst.EnvDiff()
+
+		// --- Then ---
+		assert.True(t, have.IsEmpty())
+	})
+}
+
+func Test_Tester_AssertEnvSet(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A=1", "B=2"}))
+		rng := tst.Ring()
+		rng.EnvSet("A", "-1")
+		rng.EnvSet("B", "2")
+		rng.EnvSet("C", "3")
+
+		// --- When ---
+		have := tst.AssertEnvSet(map[string]string{"A": "-1", "C": "3"})
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("success - nothing set", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A=1"}))
+		tst.Ring().EnvUnset("A")
+
+		// --- When ---
+		have := tst.AssertEnvSet(nil)
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("error - different variables set", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.ExpectError()
+		tspy.IgnoreLogs()
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv(nil))
+		tst.Ring().EnvSet("A", "1")
+
+		// --- When ---
+		have := tst.AssertEnvSet(map[string]string{"A": "2"})
+
+		// --- Then ---
+		assert.False(t, have)
+	})
+}
+
+func Test_Tester_AssertEnvUnset(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A=1", "B=2", "C=3"}))
+		rng := tst.Ring()
+		rng.EnvUnset("C")
+		rng.EnvUnset("A")
+		rng.EnvUnset("X")
+
+		// --- When ---
+		have := tst.AssertEnvUnset("C", "A")
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("success - nothing unset", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A=1"}))
+		tst.Ring().EnvSet("A", "2")
+
+		// --- When ---
+		have := tst.AssertEnvUnset()
+
+		// --- Then ---
+		assert.True(t, have)
+	})
+
+	t.Run("error - different variables unset", func(t *testing.T) {
+		// --- Given ---
+		tspy := tester.New(t)
+		tspy.ExpectCleanups(2)
+		tspy.ExpectError()
+		tspy.IgnoreLogs()
+		tspy.Close()
+
+		tst := New(tspy, ring.WithEnv([]string{"A=1", "B=2"}))
+		tst.Ring().EnvUnset("A")
+
+		// --- When ---
+		have := tst.AssertEnvUnset("B")
+
+		// --- Then ---
+		assert.False(t, have)
+	})
 }
 
 func Test_Tester_Streams(t *testing.T) {
@@ -0,0 +1,239 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvType represents the type of the environment variable value.
+type EnvType string
+
+// Environment variable value types.
+const (
+	TypeString   EnvType = "string"   // Any value.
+	TypeInt      EnvType = "int"      // Value parsed with [strconv.Atoi].
+	TypeFloat    EnvType = "float"    // Value parsed as a 64-bit float.
+	TypeBool     EnvType = "bool"     // Value parsed with [strconv.ParseBool].
+	TypeDuration EnvType = "duration" // Value parsed with [time.ParseDuration].
+	TypeURL      EnvType = "url"      // Value parsed with [url.Parse].
+	TypeList     EnvType = "list"     // Comma separated, see [Env.EnvList].
+)
+
+// EnvVar describes an environment variable expected by a program.
+type EnvVar struct {
+	// Variable name.
+	Name string
+
+	// Value type, when empty [TypeString] is assumed.
+	Type EnvType
+
+	// The variable must be set.
+	Required bool
+
+	// Default value used when the variable is not set.
+	Default string
+
+	// Allowed values, when empty, any value is allowed. For [TypeList], every
+	// item of the list must be allowed.
+	Allowed []string
+
+	// Value must match the regular expression, when not nil.
+	Pattern *regexp.Regexp
+
+	// Human-readable description.
+	Description string
+
+	// The value is sensitive, see [Env.EnvSensitive]. Values and defaults of
+	// sensitive variables are redacted in errors and help.
+	Sensitive bool
+}
+
+// EnvSchema represents the declaration of environment variables expected by
+// a program.
+type EnvSchema struct {
+	vars []EnvVar // Declared variables in declaration order.
+}
+
+// NewEnvSchema returns a new [EnvSchema] declaring the variables.
+func NewEnvSchema(vars ...EnvVar) *EnvSchema {
+	return &EnvSchema{vars: slices.Clone(vars)}
+}
+
+// Add declares more variables. Returns the schema for chaining.
+func (sch *EnvSchema) Add(vars ...EnvVar) *EnvSchema {
+	sch.vars = append(sch.vars, vars...)
+	return sch
+}
+
+// Vars returns declared variables in declaration order.
+func (sch *EnvSchema) Vars() []EnvVar { return slices.Clone(sch.vars) }
+
+// Sensitive returns names of variables declared as sensitive. The result
+// may be passed to [Env.EnvSensitive].
+func (sch *EnvSchema) Sensitive() []string {
+	var ret []string
+	for _, v := range sch.vars {
+		if v.Sensitive {
+			ret = append(ret, v.Name)
+		}
+	}
+	return ret
+}
+
+// Defaults sets the variables which are not set in the environment and have
+// a non-empty default value to their default values.
+func (sch *EnvSchema) Defaults(env Environ) {
+	for _, v := range sch.vars {
+		if v.Default == "" {
+			continue
+		}
+		if _, exist := env.EnvLookup(v.Name); !exist {
+			env.EnvSet(v.Name, v.Default)
+		}
+	}
+}
+
+// Validate validates the environment against the schema. It returns all
+// violations joined with [errors.Join], each wrapping [ErrReqEnv] for
+// required variables which are not set or [ErrInvEnv] for invalid values.
+// For variables which are not set, their default values are validated.
+func (sch *EnvSchema) Validate(env Environ) error {
+	var errs []error
+	for _, v := range sch.vars {
+		val, exist := env.EnvLookup(v.Name)
+		if !exist {
+			if v.Required {
+				errs = append(errs, envRequired(v.Name))
+				continue
+			}
+			if v.Default == "" {
+				continue
+			}
+			val = v.Default
+		}
+		if err := v.validate(val); err != nil {
+			shown := val
+			if v.Sensitive {
+				shown = Redacted
+			}
+			errs = append(errs, envInvalid(v.Name, shown, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Help returns a human-readable description of the declared variables.
+// See [EnvSchema.WriteHelp].
+func (sch *EnvSchema) Help() string {
+	buf := &strings.Builder{}
+	_ = sch.WriteHelp(buf)
+	return buf.String()
+}
+
+// WriteHelp writes a human-readable description of the declared variables
+// to the writer in the format similar to [flag.PrintDefaults]. Each variable
+// is described by the line with its name and type followed by the indented
+// description and attributes.
+func (sch *EnvSchema) WriteHelp(w io.Writer) error {
+	for _, v := range sch.vars {
+		if _, err := io.WriteString(w, v.help()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate validates the value against the variable declaration.
+func (v EnvVar) validate(val string) error {
+	items := []string{val}
+	switch v.Type {
+	case "", TypeString:
+	case TypeInt:
+		if _, err := strconv.Atoi(val); err != nil {
+			return err
+		}
+	case TypeFloat:
+		if _, err := parseFloat(val); err != nil {
+			return err
+		}
+	case TypeBool:
+		if _, err := strconv.ParseBool(val); err != nil {
+			return err
+		}
+	case TypeDuration:
+		if _, err := time.ParseDuration(val); err != nil {
+			// The error message repeats the value, which may be sensitive.
+			return errors.New("invalid duration")
+		}
+	case TypeURL:
+		if _, err := url.Parse(val); err != nil {
+			return err
+		}
+	case TypeList:
+		items, _ = parseList(val)
+	default:
+		return fmt.Errorf("unknown type %q", v.Type)
+	}
+	if len(v.Allowed) > 0 {
+		for _, item := range items {
+			if !slices.Contains(v.Allowed, item) {
+				return fmt.Errorf("not one of: %s", v.allowed())
+			}
+		}
+	}
+	if v.Pattern != nil && !v.Pattern.MatchString(val) {
+		return fmt.Errorf("does not match pattern: %s", v.Pattern)
+	}
+	return nil
+}
+
+// help returns the variable description for [EnvSchema.WriteHelp].
+func (v EnvVar) help() string {
+	typ := v.Type
+	if typ == "" {
+		typ = TypeString
+	}
+	buf := &strings.Builder{}
+	buf.WriteString("  " + v.Name + " " + string(typ) + "\n")
+	if v.Description != "" {
+		buf.WriteString("    \t" + v.Description + "\n")
+	}
+
+	var attrs []string
+	if v.Required {
+		attrs = append(attrs, "required")
+	}
+	if v.Default != "" {
+		def := v.Default
+		if v.Sensitive {
+			def = Redacted
+		}
+		attrs = append(attrs, "default: "+def)
+	}
+	if len(v.Allowed) > 0 {
+		attrs = append(attrs, "one of: "+v.allowed())
+	}
+	if v.Pattern != nil {
+		attrs = append(attrs, "pattern: "+v.Pattern.String())
+	}
+	if v.Sensitive {
+		attrs = append(attrs, "sensitive")
+	}
+	if len(attrs) > 0 {
+		buf.WriteString("    \t(" + strings.Join(attrs, ", ") + ")\n")
+	}
+	return buf.String()
+}
+
+// allowed returns allowed values as a comma separated list.
+func (v EnvVar) allowed() string { return strings.Join(v.Allowed, ", ") }
@@ -0,0 +1,354 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+// errWrite is the error returned by [errWriter].
+var errWrite = errors.New("write")
+
+// errWriter is an [io.Writer] always returning an error.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, errWrite }
+
+func Test_NewEnvSchema(t *testing.T) {
+	// --- Given ---
+	vars := []EnvVar{{Name: "A"}, {Name: "B"}}
+
+	// --- When ---
+	have := NewEnvSchema(vars...)
+
+	// --- Then ---
+	assert.Equal(t, vars, have.vars)
+	assert.NotSame(t, vars, have.vars)
+}
+
+func Test_EnvSchema_Add(t *testing.T) {
+	// --- Given ---
+	sch := NewEnvSchema(EnvVar{Name: "A"})
+
+	// --- When ---
+	have := sch.Add(EnvVar{Name: "B"}, EnvVar{Name: "C"})
+
+	// --- Then ---
+	assert.Same(t, sch, have)
+	want := []EnvVar{{Name: "A"}, {Name: "B"}, {Name: "C"}}
+	assert.Equal(t, want, sch.vars)
+}
+
+func Test_EnvSchema_Vars(t *testing.T) {
+	// --- Given ---
+	sch := NewEnvSchema(EnvVar{Name: "A"}, EnvVar{Name: "B"})
+
+	// --- When ---
+	have := sch.Vars()
+
+	// --- Then ---
+	assert.Equal(t, []EnvVar{{Name: "A"}, {Name: "B"}}, have)
+	assert.NotSame(t, sch.vars, have)
+}
+
+func Test_EnvSchema_Sensitive(t *testing.T) {
+	t.Run("sensitive", func(t *testing.T) {
+		// --- Given ---
+		sch := NewEnvSchema(
+			EnvVar{Name: "A", Sensitive: true},
+			EnvVar{Name: "B"},
+			EnvVar{Name: "C", Sensitive: true},
+		)
+
+		// --- When ---
+		have := sch.Sensitive()
+
+		// --- Then ---
+		assert.Equal(t, []string{"A", "C"}, have)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		// --- Given ---
+		sch := NewEnvSchema(EnvVar{Name: "A"})
+
+		// --- When ---
+		have := sch.Sensitive()
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_EnvSchema_Defaults(t *testing.T) {
+	// --- Given ---
+	sch := NewEnvSchema(
+		EnvVar{Name: "A", Default: "a"},
+		EnvVar{Name: "B", Default: "b"},
+		EnvVar{Name: "C"},
+	)
+	env := NewEnv([]string{"A=1"})
+
+	// --- When ---
+	sch.Defaults(env)
+
+	// --- Then ---
+	assert.Equal(t, []string{"A=1", "B=b"}, env.EnvAll())
+}
+
+func Test_EnvSchema_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		// --- Given ---
+		sch := NewEnvSchema(
+			EnvVar{Name: "HOST", Required: true},
+			EnvVar{Name: "PORT", Type: TypeInt, Default: "5432"},
+			EnvVar{Name: "OPT"},
+		)
+		env := NewEnv([]string{"HOST=localhost"})
+
+		// --- When ---
+		err := sch.Validate(env)
+
+		// --- Then ---
+		assert.NoError(t, err)
+	})
+
+	t.Run("error - required", func(t *testing.T) {
+		// --- Given ---
+		sch := NewEnvSchema(EnvVar{Name: "A", Required: true, Default: "a"})
+
+		// --- When ---
+		err := sch.Validate(NewEnv(nil))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReqEnv, err)
+		assert.ErrorEqual(t, "required environment variable: A", err)
+	})
+
+	t.Run("error - invalid default", func(t *testing.T) {
+		// --- Given ---
+		sch := NewEnvSchema(EnvVar{Name: "A", Type: TypeInt, Default: "a"})
+
+		// --- When ---
+		err := sch.Validate(NewEnv(nil))
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := `invalid environment variable: A="a": invalid syntax`
+		assert.ErrorEqual(t, wMsg, err)
+	})
+
+	t.Run("error - all violations", func(t *testing.T) {
+		// --- Given ---
+		sch := NewEnvSchema(
+			EnvVar{Name: "A", Required: true},
+			EnvVar{Name: "B", Type: TypeBool},
+			EnvVar{Name: "C", Allowed: []string{"x", "y"}},
+		)
+		env := NewEnv([]string{"B=abc", "C=z"})
+
+		// --- When ---
+		err := sch.Validate(env)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrReqEnv, err)
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "required environment variable: A\n" +
+			`invalid environment variable: B="abc": invalid syntax` + "\n" +
+			`invalid environment variable: C="z": not one of: x, y`
+		assert.ErrorEqual(t, wMsg, err)
+	})
+
+	t.Run("error - sensitive value is redacted", func(t *testing.T) {
+		// --- Given ---
+		sch := NewEnvSchema(EnvVar{
+			Name:      "TOKEN",
+			Pattern:   regexp.MustCompile(`^[a-f0-9]+$`),
+			Sensitive: true,
+		})
+		env := NewEnv([]string{"TOKEN=secret"})
+
+		// --- When ---
+		err := sch.Validate(env)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := `invalid environment variable: TOKEN="[REDACTED]": ` +
+			`does not match pattern: ^[a-f0-9]+$`
+		assert.ErrorEqual(t, wMsg, err)
+	})
+
+	t.Run("ring", func(t *testing.T) {
+		// --- Given ---
+		sch := NewEnvSchema(EnvVar{Name: "A", Type: TypeInt})
+		rng := New(WithEnv([]string{"A=x"}))
+
+		// --- When ---
+		err := sch.Validate(rng)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+	})
+}
+
+func Test_EnvSchema_Help(t *testing.T) {
+	// --- Given ---
+	sch := NewEnvSchema(
+		EnvVar{Name: "HOST", Required: true, Description: "Database host."},
+		EnvVar{Name: "PORT", Type: TypeInt, Default: "5432"},
+		EnvVar{
+			Name:        "LEVEL",
+			Allowed:     []string{"debug", "info"},
+			Pattern:     regexp.MustCompile(`^[a-z]+$`),
+			Description: "Log level.",
+		},
+		EnvVar{
+			Name:      "TOKEN",
+			Default:   "secret",
+			Sensitive: true,
+		},
+	)
+
+	// --- When ---
+	have := sch.Help()
+
+	// --- Then ---
+	want := "" +
+		"  HOST string\n" +
+		"    \tDatabase host.\n" +
+		"    \t(required)\n" +
+		"  PORT int\n" +
+		"    \t(default: 5432)\n" +
+		"  LEVEL string\n" +
+		"    \tLog level.\n" +
+		"    \t(one of: debug, info, pattern: ^[a-z]+$)\n" +
+		"  TOKEN string\n" +
+		"    \t(default: [REDACTED], sensitive)\n"
+	assert.Equal(t, want, have)
+}
+
+func Test_EnvSchema_WriteHelp(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		sch := NewEnvSchema(EnvVar{Name: "A", Type: TypeBool})
+		buf := &bytes.Buffer{}
+
+		// --- When ---
+		err := sch.WriteHelp(buf)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "  A bool\n", buf.String())
+	})
+
+	t.Run("error - write", func(t *testing.T) {
+		// --- Given ---
+		sch := NewEnvSchema(EnvVar{Name: "A"})
+
+		// --- When ---
+		err := sch.WriteHelp(errWriter{})
+
+		// --- Then ---
+		assert.ErrorIs(t, errWrite, err)
+	})
+}
+
+func Test_EnvVar_validate_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		v    EnvVar
+		val  string
+		wMsg string
+	}{
+		{"string", EnvVar{}, "abc", ""},
+		{"string explicit", EnvVar{Type: TypeString}, "abc", ""},
+		{"int", EnvVar{Type: TypeInt}, "42", ""},
+		{
+			"int invalid",
+			EnvVar{Type: TypeInt},
+			"4.2",
+			`strconv.Atoi: parsing "4.2": invalid syntax`,
+		},
+		{"float", EnvVar{Type: TypeFloat}, "4.2", ""},
+		{
+			"float invalid",
+			EnvVar{Type: TypeFloat},
+			"x",
+			`strconv.ParseFloat: parsing "x": invalid syntax`,
+		},
+		{"bool", EnvVar{Type: TypeBool}, "true", ""},
+		{
+			"bool invalid",
+			EnvVar{Type: TypeBool},
+			"yes",
+			`strconv.ParseBool: parsing "yes": invalid syntax`,
+		},
+		{"duration", EnvVar{Type: TypeDuration}, "1s", ""},
+		{
+			"duration invalid",
+			EnvVar{Type: TypeDuration},
+			"1x",
+			"invalid duration",
+		},
+		{"url", EnvVar{Type: TypeURL}, "http://host", ""},
+		{
+			"url invalid",
+			EnvVar{Type: TypeURL},
+			":x",
+			`parse ":x": missing protocol scheme`,
+		},
+		{"list", EnvVar{Type: TypeList}, "a, b", ""},
+		{"unknown type", EnvVar{Type: "abc"}, "x", `unknown type "abc"`},
+		{"allowed", EnvVar{Allowed: []string{"a", "b"}}, "b", ""},
+		{
+			"not allowed",
+			EnvVar{Allowed: []string{"a", "b"}},
+			"c",
+			"not one of: a, b",
+		},
+		{
+			"list allowed",
+			EnvVar{Type: TypeList, Allowed: []string{"a", "b"}},
+			"b,a",
+			"",
+		},
+		{
+			"list not allowed",
+			EnvVar{Type: TypeList, Allowed: []string{"a", "b"}},
+			"a,c",
+			"not one of: a, b",
+		},
+		{
+			"pattern",
+			EnvVar{Pattern: regexp.MustCompile(`^\d+$`)},
+			"123",
+			"",
+		},
+		{
+			"pattern no match",
+			EnvVar{Pattern: regexp.MustCompile(`^\d+$`)},
+			"12a",
+			`does not match pattern: ^\d+$`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			err := tc.v.validate(tc.val)
+
+			// --- Then ---
+			if tc.wMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorEqual(t, tc.wMsg, err)
+			}
+		})
+	}
+}
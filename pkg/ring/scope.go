@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"strings"
+)
+
+var _ Environ = &EnvScope{} // Compile time check.
+
+// EnvScope is an [Environ] view of variables with names starting with a
+// prefix. Keys passed to the view are prefixed before reaching the
+// underlying environment, and keys returned by it have the prefix stripped.
+// For example, in the view with the "MYAPP_" prefix, the "DB_HOST" key refers
+// to the "MYAPP_DB_HOST" variable.
+//
+// The view holds no state of its own, changes made through it are visible
+// in the underlying environment and the other way around.
+type EnvScope struct {
+	env    Environ // The underlying environment.
+	prefix string  // Variable name prefix.
+}
+
+// NewEnvScope returns a new [EnvScope] view of the environment variables with
+// names starting with the prefix.
+func NewEnvScope(env Environ, prefix string) *EnvScope {
+	return &EnvScope{env: env, prefix: prefix}
+}
+
+// EnvScope returns a view of the environment variables with names starting
+// with the prefix. See [EnvScope] for details.
+func (env *Env) EnvScope(prefix string) *EnvScope {
+	return NewEnvScope(env, prefix)
+}
+
+// Prefix returns the variable name prefix of the view.
+func (scp *EnvScope) Prefix() string { return scp.prefix }
+
+// EnvLookup retrieves the value of the prefixed variable named by the key.
+func (scp *EnvScope) EnvLookup(key string) (string, bool) {
+	return scp.env.EnvLookup(scp.prefix + key)
+}
+
+// EnvGet retrieves the value of the prefixed variable named by the key.
+func (scp *EnvScope) EnvGet(key string) string {
+	return scp.env.EnvGet(scp.prefix + key)
+}
+
+// EnvSet sets the prefixed variable named by the key.
+func (scp *EnvScope) EnvSet(key, value string) {
+	scp.env.EnvSet(scp.prefix+key, value)
+}
+
+// EnvUnset unsets the prefixed variable named by the key.
+func (scp *EnvScope) EnvUnset(key string) {
+	scp.env.EnvUnset(scp.prefix + key)
+}
+
+// EnvAll returns variables with names starting with the prefix as a slice
+// of "key=value" entries with the prefix stripped from the keys. The order
+// of the underlying environment is preserved. It returns nil when there are
+// no such variables. Variables named exactly as the prefix are skipped.
+func (scp *EnvScope) EnvAll() []string {
+	var ret []string
+	for _, entry := range scp.env.EnvAll() {
+		rest, ok := strings.CutPrefix(entry, scp.prefix)
+		if !ok || rest == "" || rest[0] == '=' {
+			continue
+		}
+		ret = append(ret, rest)
+	}
+	return ret
+}
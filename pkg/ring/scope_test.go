@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"testing"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+func Test_NewEnvScope(t *testing.T) {
+	// --- Given ---
+	env := NewEnv(nil)
+
+	// --- When ---
+	have := NewEnvScope(env, "APP_")
+
+	// --- Then ---
+	assert.Same(t, env, have.env)
+	assert.Equal(t, "APP_", have.prefix)
+	assert.Fields(t, 2, EnvScope{})
+}
+
+func Test_Env_EnvScope(t *testing.T) {
+	// --- Given ---
+	env := NewEnv(nil)
+
+	// --- When ---
+	have := env.EnvScope("APP_")
+
+	// --- Then ---
+	assert.Same(t, env, have.env)
+	assert.Equal(t, "APP_", have.prefix)
+}
+
+func Test_EnvScope_Prefix(t *testing.T) {
+	// --- Given ---
+	scp := NewEnvScope(NewEnv(nil), "APP_")
+
+	// --- When ---
+	have := scp.Prefix()
+
+	// --- Then ---
+	assert.Equal(t, "APP_", have)
+}
+
+func Test_EnvScope_EnvLookup(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"APP_DB_HOST=host", "DB_HOST=other"})
+		scp := NewEnvScope(env, "APP_")
+
+		// --- When ---
+		have, exist := scp.EnvLookup("DB_HOST")
+
+		// --- Then ---
+		assert.True(t, exist)
+		assert.Equal(t, "host", have)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"DB_HOST=other"})
+		scp := NewEnvScope(env, "APP_")
+
+		// --- When ---
+		have, exist := scp.EnvLookup("DB_HOST")
+
+		// --- Then ---
+		assert.False(t, exist)
+		assert.Equal(t, "", have)
+	})
+}
+
+func Test_EnvScope_EnvGet(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"APP_DB_HOST=host", "DB_HOST=other"})
+	scp := NewEnvScope(env, "APP_")
+
+	// --- When ---
+	have := scp.EnvGet("DB_HOST")
+
+	// --- Then ---
+	assert.Equal(t, "host", have)
+}
+
+func Test_EnvScope_EnvSet(t *testing.T) {
+	// --- Given ---
+	env := NewEnv(nil)
+	scp := NewEnvScope(env, "APP_")
+
+	// --- When ---
+	scp.EnvSet("DB_HOST", "host")
+
+	// --- Then ---
+	assert.Equal(t, []string{"APP_DB_HOST=host"}, env.EnvAll())
+}
+
+func Test_EnvScope_EnvUnset(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"APP_DB_HOST=host", "DB_HOST=other"})
+	scp := NewEnvScope(env, "APP_")
+
+	// --- When ---
+	scp.EnvUnset("DB_HOST")
+
+	// --- Then ---
+	assert.Equal(t, []string{"DB_HOST=other"}, env.EnvAll())
+}
+
+func Test_EnvScope_EnvAll(t *testing.T) {
+	t.Run("scoped keys", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{
+			"APP_B=2",
+			"APP_A=1",
+			"APP=3",
+			"APP_=4",
+			"APPX=5",
+			"B=6",
+		})
+		scp := NewEnvScope(env, "APP_")
+
+		// --- When ---
+		have := scp.EnvAll()
+
+		// --- Then ---
+		assert.Equal(t, []string{"A=1", "B=2"}, have)
+	})
+
+	t.Run("empty prefix", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"B=2", "A=1"})
+		scp := NewEnvScope(env, "")
+
+		// --- When ---
+		have := scp.EnvAll()
+
+		// --- Then ---
+		assert.Equal(t, []string{"A=1", "B=2"}, have)
+	})
+
+	t.Run("no scoped keys", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"A=1"})
+		scp := NewEnvScope(env, "APP_")
+
+		// --- When ---
+		have := scp.EnvAll()
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_EnvScope_nested(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"APP_DB_HOST=host", "APP_DB_PORT=5432"})
+	scp := NewEnvScope(env.EnvScope("APP_"), "DB_")
+
+	// --- When ---
+	scp.EnvSet("USER", "usr")
+
+	// --- Then ---
+	assert.Equal(t, "host", scp.EnvGet("HOST"))
+	want := []string{"HOST=host", "PORT=5432", "USER=usr"}
+	assert.Equal(t, want, scp.EnvAll())
+	assert.Equal(t, "usr", env.EnvGet("APP_DB_USER"))
+}
+
+func Test_EnvScope_with_decode(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"APP_NAME=name", "NAME=other"})
+	var dst struct {
+		Name string `env:"NAME"`
+	}
+
+	// --- When ---
+	err := EnvDecode(env.EnvScope("APP_"), &dst)
+
+	// --- Then ---
+	assert.NoError(t, err)
+	assert.Equal(t, "name", dst.Name)
+}
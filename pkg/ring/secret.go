@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// Redacted is the value replacing sensitive values in redacted views.
+const Redacted = "[REDACTED]"
+
+var _ slog.LogValuer = &Env{} // Compile time check.
+
+// EnvSensitive marks variables matching any of the patterns as sensitive.
+// A pattern is either an exact variable name or contains "*" wildcards
+// matching any sequence of characters, for example, "*_TOKEN" or "*SECRET*".
+//
+// Sensitive values are masked in [Env.EnvRedacted], [Env.String],
+// [Env.GoString] and [Env.LogValue] output, while [Env.EnvGet] and
+// [Env.EnvLookup] return the real values.
+func (env *Env) EnvSensitive(patterns ...string) {
+	defer env.lock()()
+	for _, pattern := range patterns {
+		if !slices.Contains(env.secrets, pattern) {
+			env.secrets = append(env.secrets, pattern)
+		}
+	}
+}
+
+// EnvIsSensitive returns true if the variable named by the key matches any
+// of the patterns registered with [Env.EnvSensitive], including patterns
+// registered in the parent of a layered environment. For case-insensitive
+// environments, the patterns are matched case-insensitively.
+func (env *Env) EnvIsSensitive(key string) bool {
+	return env.sensitive()(key)
+}
+
+// EnvRedacted returns the sorted environment as a slice of "key=value"
+// entries with sensitive values replaced by [Redacted]. It returns nil when
+// the environment is empty.
+func (env *Env) EnvRedacted() []string {
+	all := env.EnvAll()
+	sensitive := env.sensitive()
+	for i, entry := range all {
+		key, _, _ := strings.Cut(entry, "=")
+		if sensitive(key) {
+			all[i] = key + "=" + Redacted
+		}
+	}
+	return all
+}
+
+// String returns the redacted environment. See [Env.EnvRedacted].
+func (env *Env) String() string { return fmt.Sprint(env.EnvRedacted()) }
+
+// GoString returns the redacted environment in Go syntax.
+func (env *Env) GoString() string {
+	return fmt.Sprintf("ring.NewEnv(%#v)", env.EnvRedacted())
+}
+
+// LogValue implements [slog.LogValuer] returning the redacted environment as
+// a group of attributes sorted by name.
+func (env *Env) LogValue() slog.Value {
+	entries := env.EnvRedacted()
+	attrs := make([]slog.Attr, 0, len(entries))
+	for _, entry := range entries {
+		key, val, _ := strings.Cut(entry, "=")
+		attrs = append(attrs, slog.String(key, val))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// secretPatterns returns a copy of sensitive variable name patterns
+// registered in the environment and its parents, see [asEnv].
+func (env *Env) secretPatterns() []string {
+	unlock := env.rlock()
+	patterns := slices.Clone(env.secrets)
+	unlock()
+	if parent := asEnv(env.parent); parent != nil {
+		patterns = append(patterns, parent.secretPatterns()...)
+	}
+	return patterns
+}
+
+// sensitive returns a function reporting if the variable named by the key
+// matches any of the patterns returned by [Env.secretPatterns].
+func (env *Env) sensitive() func(key string) bool {
+	patterns := env.secretPatterns()
+	for i, pattern := range patterns {
+		patterns[i] = env.fold(pattern)
+	}
+	return func(key string) bool { return isSensitive(patterns, env.fold(key)) }
+}
+
+// isSensitive returns true if the key matches any of the patterns.
+func isSensitive(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlob returns true if the name matches the pattern where "*" matches
+// any sequence of characters.
+func matchGlob(pattern, name string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == name
+	}
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(name, part)
+		if idx < 0 {
+			return false
+		}
+		name = name[idx+len(part):]
+	}
+	return strings.HasSuffix(name, last)
+}
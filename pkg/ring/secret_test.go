@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+func Test_Env_EnvSensitive(t *testing.T) {
+	t.Run("add patterns", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		env.EnvSensitive("A", "*_TOKEN")
+
+		// --- Then ---
+		assert.Equal(t, []string{"A", "*_TOKEN"}, env.secrets)
+	})
+
+	t.Run("duplicates are ignored", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		env.EnvSensitive("A")
+
+		// --- When ---
+		env.EnvSensitive("A", "B")
+
+		// --- Then ---
+		assert.Equal(t, []string{"A", "B"}, env.secrets)
+	})
+}
+
+func Test_Env_EnvIsSensitive(t *testing.T) {
+	t.Run("exact and pattern", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+		env.EnvSensitive("PASSWORD", "*_TOKEN")
+
+		// --- Then ---
+		assert.True(t, env.EnvIsSensitive("PASSWORD"))
+		assert.True(t, env.EnvIsSensitive("GH_TOKEN"))
+		assert.False(t, env.EnvIsSensitive("DB_PASSWORD"))
+		assert.False(t, env.EnvIsSensitive("TOKEN_FILE"))
+	})
+
+	t.Run("parent patterns", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv(nil)
+		parent.EnvSensitive("A")
+		env := NewEnvLayer(parent)
+		env.EnvSensitive("B")
+
+		// --- Then ---
+		assert.True(t, env.EnvIsSensitive("A"))
+		assert.True(t, env.EnvIsSensitive("B"))
+		assert.False(t, parent.EnvIsSensitive("B"))
+	})
+
+	t.Run("audited parent patterns", func(t *testing.T) {
+		// --- Given ---
+		parent := NewEnv(nil)
+		parent.EnvSensitive("A")
+		env := NewEnvLayer(NewEnvAudit(parent))
+
+		// --- Then ---
+		assert.True(t, env.EnvIsSensitive("A"))
+	})
+
+	t.Run("case-insensitive", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil, WithCaseInsensitive())
+		env.EnvSensitive("Password", "*_token")
+
+		// --- Then ---
+		assert.True(t, env.EnvIsSensitive("PASSWORD"))
+		assert.True(t, env.EnvIsSensitive("gh_Token"))
+		assert.False(t, env.EnvIsSensitive("TOKEN_FILE"))
+	})
+}
+
+func Test_Env_EnvRedacted(t *testing.T) {
+	t.Run("redacted and sorted", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"GH_TOKEN=abc", "B=2", "A=1", "PASS=secret"})
+		env.EnvSensitive("*_TOKEN", "PASS")
+
+		// --- When ---
+		have := env.EnvRedacted()
+
+		// --- Then ---
+		want := []string{"A=1", "B=2", "GH_TOKEN=[REDACTED]", "PASS=[REDACTED]"}
+		assert.Equal(t, want, have)
+		assert.Equal(t, "abc", env.EnvGet("GH_TOKEN"))
+	})
+
+	t.Run("case-insensitive", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv([]string{"Gh_Token=abc", "A=1"}, WithCaseInsensitive())
+		env.EnvSensitive("*_TOKEN")
+
+		// --- When ---
+		have := env.EnvRedacted()
+
+		// --- Then ---
+		assert.Equal(t, []string{"A=1", "Gh_Token=[REDACTED]"}, have)
+	})
+
+	t.Run("empty environment", func(t *testing.T) {
+		// --- Given ---
+		env := NewEnv(nil)
+
+		// --- When ---
+		have := env.EnvRedacted()
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+}
+
+func Test_Env_String(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"B=2", "A_TOKEN=abc"})
+	env.EnvSensitive("*_TOKEN")
+
+	// --- When ---
+	have := fmt.Sprintf("%s|%v", env, env)
+
+	// --- Then ---
+	want := "[A_TOKEN=[REDACTED] B=2]|[A_TOKEN=[REDACTED] B=2]"
+	assert.Equal(t, want, have)
+}
+
+func Test_Env_GoString(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"B=2", "A_TOKEN=abc"})
+	env.EnvSensitive("*_TOKEN")
+
+	// --- When ---
+	have := fmt.Sprintf("%#v", env)
+
+	// --- Then ---
+	want := `ring.NewEnv([]string{"A_TOKEN=[REDACTED]", "B=2"})`
+	assert.Equal(t, want, have)
+}
+
+func Test_Env_LogValue(t *testing.T) {
+	// --- Given ---
+	env := NewEnv([]string{"B=2", "A_TOKEN=abc"})
+	env.EnvSensitive("*_TOKEN")
+	buf := &bytes.Buffer{}
+	log := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+
+	// --- When ---
+	log.Info("msg", "env", env)
+
+	// --- Then ---
+	want := "level=INFO msg=msg env.A_TOKEN=[REDACTED] env.B=2\n"
+	assert.Equal(t, want, buf.String())
+}
+
+func Test_matchGlob_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"exact match", "A", "A", true},
+		{"exact no match", "A", "AB", false},
+		{"star only", "*", "ANY", true},
+		{"star matches empty", "*", "", true},
+		{"suffix", "*_TOKEN", "GH_TOKEN", true},
+		{"suffix exact", "*_TOKEN", "_TOKEN", true},
+		{"suffix no match", "*_TOKEN", "TOKEN", false},
+		{"prefix", "AWS_*", "AWS_KEY", true},
+		{"prefix no match", "AWS_*", "MY_AWS_KEY", false},
+		{"contains", "*SECRET*", "MY_SECRET_KEY", true},
+		{"contains no match", "*SECRET*", "MY_SECRE", false},
+		{"middle", "A*B*C", "AxxBxxC", true},
+		{"middle no match", "A*B*C", "AxxCxxB", false},
+		{"no overlap", "AB*BC", "ABC", false},
+		{"case sensitive", "*_token", "GH_TOKEN", false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have := matchGlob(tc.pattern, tc.name)
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
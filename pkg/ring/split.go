@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// EnvDup represents the policy for handling duplicate keys by
+// [EnvSplitStrict].
+type EnvDup int
+
+// Duplicate key policies.
+const (
+	// DupError reports duplicate keys as an error.
+	DupError EnvDup = iota
+
+	// DupFirstWins uses the value of the first entry with the key.
+	DupFirstWins
+
+	// DupLastWins uses the value of the last entry with the key, the same
+	// way [EnvSplit] does.
+	DupLastWins
+)
+
+// EnvSplitError represents problems found by [EnvSplitStrict]. It wraps
+// [ErrInvEnv]. Entries are identified by their indexes in the input slice.
+// Values are never included in the error, as they may be sensitive.
+type EnvSplitError struct {
+	// Indexes of entries without "=" or with an empty key.
+	Malformed []int
+
+	// Indexes of entries by duplicated key, only for [DupError] policy.
+	Duplicates map[string][]int
+}
+
+// Error returns the [ErrInvEnv] message followed by the problems, for
+// example:
+//
+//	malformed entries: 1, 4; duplicate keys: A (0, 2), B (3, 5)
+func (e *EnvSplitError) Error() string {
+	var parts []string
+	if len(e.Malformed) > 0 {
+		parts = append(parts, "malformed entries: "+joinInts(e.Malformed))
+	}
+	if len(e.Duplicates) > 0 {
+		var keys []string
+		for _, key := range slices.Sorted(maps.Keys(e.Duplicates)) {
+			keys = append(keys, key+" ("+joinInts(e.Duplicates[key])+")")
+		}
+		parts = append(parts, "duplicate keys: "+strings.Join(keys, ", "))
+	}
+	return ErrInvEnv.Error() + ": " + strings.Join(parts, "; ")
+}
+
+// Unwrap returns [ErrInvEnv].
+func (e *EnvSplitError) Unwrap() error { return ErrInvEnv }
+
+// EnvSplitStrict parses [os.Environ] like results and returns it as a key
+// value map. Unlike [EnvSplit], which silently skips malformed entries, it
+// returns an [*EnvSplitError] listing entries without "=" or with an empty
+// key. Empty entries are skipped. Duplicate keys are handled according to
+// the policy, for [DupError] they are listed in the returned error. On
+// error, the returned map is nil.
+func EnvSplitStrict(env []string, dup EnvDup) (map[string]string, error) {
+	ret := make(map[string]string, len(env))
+	seen := make(map[string][]int, len(env))
+	e := &EnvSplitError{}
+	for i, entry := range env {
+		if entry == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(entry, "=")
+		if !ok || key == "" {
+			e.Malformed = append(e.Malformed, i)
+			continue
+		}
+		seen[key] = append(seen[key], i)
+		if _, exist := ret[key]; exist && dup == DupFirstWins {
+			continue
+		}
+		ret[key] = val
+	}
+	if dup == DupError {
+		for key, idx := range seen {
+			if len(idx) < 2 {
+				continue
+			}
+			if e.Duplicates == nil {
+				e.Duplicates = make(map[string][]int)
+			}
+			e.Duplicates[key] = idx
+		}
+	}
+	if len(e.Malformed) > 0 || len(e.Duplicates) > 0 {
+		return nil, e
+	}
+	return ret, nil
+}
+
+// joinInts returns the numbers separated by comma and space.
+func joinInts(nums []int) string {
+	strs := make([]string, 0, len(nums))
+	for _, num := range nums {
+		strs = append(strs, strconv.Itoa(num))
+	}
+	return strings.Join(strs, ", ")
+}
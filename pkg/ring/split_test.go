@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+func Test_EnvSplitError_Error_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		err  *EnvSplitError
+		want string
+	}{
+		{
+			"malformed",
+			&EnvSplitError{Malformed: []int{1, 4}},
+			"invalid environment variable: malformed entries: 1, 4",
+		},
+		{
+			"duplicates",
+			&EnvSplitError{
+				Duplicates: map[string][]int{"B": {3, 5}, "A": {0, 2}},
+			},
+			"invalid environment variable: duplicate keys: A (0, 2), B (3, 5)",
+		},
+		{
+			"malformed and duplicates",
+			&EnvSplitError{
+				Malformed:  []int{1},
+				Duplicates: map[string][]int{"A": {0, 2, 3}},
+			},
+			"invalid environment variable: malformed entries: 1; " +
+				"duplicate keys: A (0, 2, 3)",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have := tc.err.Error()
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func Test_EnvSplitError_Unwrap(t *testing.T) {
+	// --- Given ---
+	err := &EnvSplitError{Malformed: []int{0}}
+
+	// --- When ---
+	have := err.Unwrap()
+
+	// --- Then ---
+	assert.Same(t, ErrInvEnv, have)
+}
+
+func Test_EnvSplitStrict(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		// --- Given ---
+		env := []string{"A=1", "", "B=", "C=x=y"}
+
+		// --- When ---
+		have, err := EnvSplitStrict(env, DupError)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		want := map[string]string{"A": "1", "B": "", "C": "x=y"}
+		assert.Equal(t, want, have)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		// --- When ---
+		have, err := EnvSplitStrict(nil, DupError)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.NotNil(t, have)
+		assert.Len(t, 0, have)
+	})
+
+	t.Run("first wins", func(t *testing.T) {
+		// --- Given ---
+		env := []string{"A=1", "B=2", "A=3", "A=4"}
+
+		// --- When ---
+		have, err := EnvSplitStrict(env, DupFirstWins)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"A": "1", "B": "2"}, have)
+	})
+
+	t.Run("last wins", func(t *testing.T) {
+		// --- Given ---
+		env := []string{"A=1", "B=2", "A=3", "A=4"}
+
+		// --- When ---
+		have, err := EnvSplitStrict(env, DupLastWins)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"A": "4", "B": "2"}, have)
+	})
+
+	t.Run("error - duplicates", func(t *testing.T) {
+		// --- Given ---
+		env := []string{"A=1", "B=2", "A=3", "C=4", "B=5", "A=6"}
+
+		// --- When ---
+		have, err := EnvSplitStrict(env, DupError)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		var e *EnvSplitError
+		assert.True(t, errors.As(err, &e))
+		assert.Nil(t, e.Malformed)
+		wDups := map[string][]int{"A": {0, 2, 5}, "B": {1, 4}}
+		assert.Equal(t, wDups, e.Duplicates)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - malformed", func(t *testing.T) {
+		// --- Given ---
+		env := []string{"A=1", "secret", "=2", "B=3"}
+
+		// --- When ---
+		have, err := EnvSplitStrict(env, DupLastWins)
+
+		// --- Then ---
+		assert.ErrorIs(t, ErrInvEnv, err)
+		wMsg := "invalid environment variable: malformed entries: 1, 2"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - malformed and duplicates", func(t *testing.T) {
+		// --- Given ---
+		env := []string{"A=1", "B", "A=2"}
+
+		// --- When ---
+		have, err := EnvSplitStrict(env, DupError)
+
+		// --- Then ---
+		wMsg := "invalid environment variable: malformed entries: 1; " +
+			"duplicate keys: A (0, 2)"
+		assert.ErrorEqual(t, wMsg, err)
+		assert.Nil(t, have)
+	})
+
+	t.Run("error - malformed with first wins", func(t *testing.T) {
+		// --- Given ---
+		env := []string{"A=1", "A=2", "B"}
+
+		// --- When ---
+		have, err := EnvSplitStrict(env, DupFirstWins)
+
+		// --- Then ---
+		var e *EnvSplitError
+		assert.True(t, errors.As(err, &e))
+		assert.Equal(t, []int{2}, e.Malformed)
+		assert.Nil(t, e.Duplicates)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_joinInts_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		nums []int
+		want string
+	}{
+		{"nil", nil, ""},
+		{"one", []int{1}, "1"},
+		{"many", []int{1, 20, 3}, "1, 20, 3"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have := joinInts(tc.nums)
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
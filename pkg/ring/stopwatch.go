@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stopwatch measures elapsed time using a [Timekeeper]. It supports laps
+// and pauses. Time passed while the stopwatch is paused is not counted.
+//
+// With an injected [Timekeeper], for example, a fake clock, measurements are
+// deterministic. For the default [Ring] clock, which tells time with [NowUTC]
+// stripping monotonic clock readings, the monotonic clock is used, so
+// measurements are not affected by wall clock changes.
+//
+// It is safe for concurrent use.
+type Stopwatch struct {
+	now     func() time.Time // Returns the current time.
+	start   time.Time        // Start of the current running period.
+	elapsed time.Duration    // Time elapsed before the current period.
+	running bool             // True when the stopwatch is running.
+	lapAt   time.Duration    // Elapsed time of the last lap.
+	laps    []time.Duration  // Recorded laps.
+	mx      sync.Mutex       // Guards all fields.
+}
+
+// NewStopwatch returns a new running [Stopwatch] using the [Timekeeper].
+func NewStopwatch(tk Timekeeper) *Stopwatch {
+	sw := &Stopwatch{now: monotonicNow(tk), running: true}
+	sw.start = sw.now()
+	return sw
+}
+
+// Stopwatch returns a new running [Stopwatch] using the [Ring] clock.
+func (rng *Ring) Stopwatch() *Stopwatch { return NewStopwatch(rng.clock) }
+
+// Elapsed returns the total time the stopwatch has been running.
+func (sw *Stopwatch) Elapsed() time.Duration {
+	sw.mx.Lock()
+	defer sw.mx.Unlock()
+	return sw.total()
+}
+
+// Running returns true if the stopwatch is running.
+func (sw *Stopwatch) Running() bool {
+	sw.mx.Lock()
+	defer sw.mx.Unlock()
+	return sw.running
+}
+
+// Pause pauses the stopwatch and returns the total elapsed time. Pausing a
+// paused stopwatch has no effect.
+func (sw *Stopwatch) Pause() time.Duration {
+	sw.mx.Lock()
+	defer sw.mx.Unlock()
+	sw.elapsed = sw.total()
+	sw.running = false
+	return sw.elapsed
+}
+
+// Resume resumes the paused stopwatch. Resuming a running stopwatch has no
+// effect.
+func (sw *Stopwatch) Resume() {
+	sw.mx.Lock()
+	defer sw.mx.Unlock()
+	if !sw.running {
+		sw.start = sw.now()
+		sw.running = true
+	}
+}
+
+// Lap records and returns the time elapsed since the previous lap, or since
+// the stopwatch was started for the first lap.
+func (sw *Stopwatch) Lap() time.Duration {
+	sw.mx.Lock()
+	defer sw.mx.Unlock()
+	total := sw.total()
+	lap := total - sw.lapAt
+	sw.lapAt = total
+	sw.laps = append(sw.laps, lap)
+	return lap
+}
+
+// Laps returns recorded laps in order. Returns nil when there are no laps.
+func (sw *Stopwatch) Laps() []time.Duration {
+	sw.mx.Lock()
+	defer sw.mx.Unlock()
+	return slices.Clone(sw.laps)
+}
+
+// Reset sets the elapsed time to zero and removes recorded laps. The
+// running state is not changed.
+func (sw *Stopwatch) Reset() {
+	sw.mx.Lock()
+	defer sw.mx.Unlock()
+	sw.start = sw.now()
+	sw.elapsed = 0
+	sw.lapAt = 0
+	sw.laps = nil
+}
+
+// String returns the elapsed time formatted with [FormatDuration].
+func (sw *Stopwatch) String() string { return FormatDuration(sw.Elapsed()) }
+
+// total returns the total elapsed time. The caller must hold the lock.
+func (sw *Stopwatch) total() time.Duration {
+	if !sw.running {
+		return sw.elapsed
+	}
+	return sw.elapsed + sw.now().Sub(sw.start)
+}
+
+// monotonicNow returns the function returning the current time of the
+// [Timekeeper]. For the default [Ring] clock it returns [time.Now], which,
+// unlike [NowUTC], keeps the monotonic clock reading.
+func monotonicNow(tk Timekeeper) func() time.Time {
+	if _, ok := tk.(*systemClock); ok {
+		return time.Now
+	}
+	return tk.Now
+}
+
+// FormatDuration returns a short human-readable representation of the
+// duration, rounded to at most two units or one decimal place, for example:
+// "850ns", "12.3µs", "350ms", "1.2s", "2m3s", "1h5m", "2d3h".
+func FormatDuration(d time.Duration) string {
+	if d < 0 {
+		if d == -d { // The minimum duration.
+			d++
+		}
+		return "-" + FormatDuration(-d)
+	}
+	const day = 24 * time.Hour
+	switch {
+	case d < time.Microsecond:
+		return strconv.FormatInt(int64(d), 10) + "ns"
+	case d < time.Millisecond:
+		return formatDecimal(d, time.Microsecond, time.Millisecond, "µs")
+	case d < time.Second:
+		return formatDecimal(d, time.Millisecond, time.Second, "ms")
+	case d < time.Minute:
+		return formatDecimal(d, time.Second, time.Minute, "s")
+	case d < time.Hour:
+		return formatUnits(d, time.Minute, time.Second, time.Hour, "m", "s")
+	case d < day:
+		return formatUnits(d, time.Hour, time.Minute, day, "h", "m")
+	default:
+		return formatUnits(d, day, time.Hour, 0, "d", "h")
+	}
+}
+
+// formatDecimal formats the duration in the unit with one decimal place.
+// When the rounded duration reaches the limit, it is formatted with
+// [FormatDuration] in the next unit.
+func formatDecimal(d, unit, limit time.Duration, suffix string) string {
+	r := d.Round(unit / 10)
+	if r >= limit {
+		return FormatDuration(r)
+	}
+	val := strconv.FormatFloat(float64(r)/float64(unit), 'f', 1, 64)
+	return strings.TrimSuffix(val, ".0") + suffix
+}
+
+// formatUnits formats the duration as a number of major and minor units.
+// When the rounded duration reaches the limit (if not zero), it is formatted
+// with [FormatDuration] in the next unit.
+func formatUnits(d, major, minor, limit time.Duration, mjr, mnr string) string {
+	r := d.Round(minor)
+	if limit > 0 && r >= limit {
+		return FormatDuration(r)
+	}
+	ret := strconv.FormatInt(int64(r/major), 10) + mjr
+	if rest := r % major / minor; rest > 0 {
+		ret += strconv.FormatInt(int64(rest), 10) + mnr
+	}
+	return ret
+}
@@ -0,0 +1,297 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ctx42/testing/pkg/assert"
+)
+
+// manualClock is a [Clock] with time moved by the test.
+type manualClock struct{ now time.Time }
+
+func (mc *manualClock) clock() Clock {
+	return func() time.Time { return mc.now }
+}
+
+func (mc *manualClock) advance(d time.Duration) { mc.now = mc.now.Add(d) }
+
+func Test_NewStopwatch(t *testing.T) {
+	// --- Given ---
+	mc := &manualClock{now: tzTime}
+
+	// --- When ---
+	have := NewStopwatch(mc.clock())
+
+	// --- Then ---
+	assert.True(t, have.running)
+	assert.Equal(t, tzTime, have.start)
+	assert.Equal(t, time.Duration(0), have.elapsed)
+	assert.Nil(t, have.laps)
+}
+
+func Test_Ring_Stopwatch(t *testing.T) {
+	// --- Given ---
+	mc := &manualClock{now: tzTime}
+	rng := New(WithClock(mc.clock()))
+
+	// --- When ---
+	have := rng.Stopwatch()
+
+	// --- Then ---
+	mc.advance(time.Second)
+	assert.Equal(t, time.Second, have.Elapsed())
+}
+
+func Test_Stopwatch_Elapsed(t *testing.T) {
+	// --- Given ---
+	mc := &manualClock{now: tzTime}
+	sw := NewStopwatch(mc.clock())
+	mc.advance(1200 * time.Millisecond)
+
+	// --- When ---
+	have := sw.Elapsed()
+
+	// --- Then ---
+	assert.Equal(t, 1200*time.Millisecond, have)
+	assert.Equal(t, "1.2s", sw.String())
+}
+
+func Test_Stopwatch_Pause(t *testing.T) {
+	t.Run("pause", func(t *testing.T) {
+		// --- Given ---
+		mc := &manualClock{now: tzTime}
+		sw := NewStopwatch(mc.clock())
+		mc.advance(time.Second)
+
+		// --- When ---
+		have := sw.Pause()
+
+		// --- Then ---
+		assert.Equal(t, time.Second, have)
+		assert.False(t, sw.Running())
+		mc.advance(time.Hour)
+		assert.Equal(t, time.Second, sw.Elapsed())
+	})
+
+	t.Run("paused", func(t *testing.T) {
+		// --- Given ---
+		mc := &manualClock{now: tzTime}
+		sw := NewStopwatch(mc.clock())
+		mc.advance(time.Second)
+		sw.Pause()
+		mc.advance(time.Hour)
+
+		// --- When ---
+		have := sw.Pause()
+
+		// --- Then ---
+		assert.Equal(t, time.Second, have)
+	})
+}
+
+func Test_Stopwatch_Resume(t *testing.T) {
+	t.Run("paused", func(t *testing.T) {
+		// --- Given ---
+		mc := &manualClock{now: tzTime}
+		sw := NewStopwatch(mc.clock())
+		mc.advance(time.Second)
+		sw.Pause()
+		mc.advance(time.Hour)
+
+		// --- When ---
+		sw.Resume()
+
+		// --- Then ---
+		assert.True(t, sw.Running())
+		mc.advance(time.Second)
+		assert.Equal(t, 2*time.Second, sw.Elapsed())
+	})
+
+	t.Run("running", func(t *testing.T) {
+		// --- Given ---
+		mc := &manualClock{now: tzTime}
+		sw := NewStopwatch(mc.clock())
+		mc.advance(time.Second)
+
+		// --- When ---
+		sw.Resume()
+
+		// --- Then ---
+		mc.advance(time.Second)
+		assert.Equal(t, 2*time.Second, sw.Elapsed())
+	})
+}
+
+func Test_Stopwatch_Lap(t *testing.T) {
+	// --- Given ---
+	mc := &manualClock{now: tzTime}
+	sw := NewStopwatch(mc.clock())
+
+	// --- When ---
+	mc.advance(time.Second)
+	lap1 := sw.Lap()
+	mc.advance(2 * time.Second)
+	sw.Pause()
+	mc.advance(time.Hour)
+	sw.Resume()
+	mc.advance(time.Second)
+	lap2 := sw.Lap()
+
+	// --- Then ---
+	assert.Equal(t, time.Second, lap1)
+	assert.Equal(t, 3*time.Second, lap2)
+	assert.Equal(t, []time.Duration{time.Second, 3 * time.Second}, sw.Laps())
+	assert.Equal(t, 4*time.Second, sw.Elapsed())
+}
+
+func Test_Stopwatch_Laps(t *testing.T) {
+	t.Run("no laps", func(t *testing.T) {
+		// --- Given ---
+		sw := NewStopwatch(Clock(NowUTC))
+
+		// --- When ---
+		have := sw.Laps()
+
+		// --- Then ---
+		assert.Nil(t, have)
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		// --- Given ---
+		mc := &manualClock{now: tzTime}
+		sw := NewStopwatch(mc.clock())
+		sw.Lap()
+
+		// --- When ---
+		have := sw.Laps()
+
+		// --- Then ---
+		assert.NotSame(t, sw.laps, have)
+	})
+}
+
+func Test_Stopwatch_Reset(t *testing.T) {
+	t.Run("running", func(t *testing.T) {
+		// --- Given ---
+		mc := &manualClock{now: tzTime}
+		sw := NewStopwatch(mc.clock())
+		mc.advance(time.Second)
+		sw.Lap()
+
+		// --- When ---
+		sw.Reset()
+
+		// --- Then ---
+		assert.Nil(t, sw.Laps())
+		assert.True(t, sw.Running())
+		mc.advance(time.Second)
+		assert.Equal(t, time.Second, sw.Elapsed())
+		assert.Equal(t, time.Second, sw.Lap())
+	})
+
+	t.Run("paused", func(t *testing.T) {
+		// --- Given ---
+		mc := &manualClock{now: tzTime}
+		sw := NewStopwatch(mc.clock())
+		mc.advance(time.Second)
+		sw.Pause()
+
+		// --- When ---
+		sw.Reset()
+
+		// --- Then ---
+		assert.False(t, sw.Running())
+		mc.advance(time.Second)
+		assert.Equal(t, time.Duration(0), sw.Elapsed())
+	})
+}
+
+func Test_monotonicNow(t *testing.T) {
+	t.Run("system clock", func(t *testing.T) {
+		// --- When ---
+		have := monotonicNow(sysClock)
+
+		// --- Then ---
+		assert.Same(t, time.Now, have)
+		assert.NotEqual(t, have().String(), have().Round(0).String())
+	})
+
+	t.Run("NowUTC clock", func(t *testing.T) {
+		// --- When ---
+		have := monotonicNow(Clock(NowUTC))
+
+		// --- Then ---
+		now := have()
+		assert.Equal(t, now.String(), now.Round(0).String())
+	})
+
+	t.Run("custom clock", func(t *testing.T) {
+		// --- Given ---
+		clk := fixedClock(tzTime)
+
+		// --- When ---
+		have := monotonicNow(clk)
+
+		// --- Then ---
+		assert.Equal(t, tzTime, have())
+	})
+
+	t.Run("timekeeper", func(t *testing.T) {
+		// --- Given ---
+		tk := &tkNow{now: tzTime}
+
+		// --- When ---
+		have := monotonicNow(tk)
+
+		// --- Then ---
+		assert.Equal(t, tzTime, have())
+	})
+}
+
+func Test_FormatDuration_tabular(t *testing.T) {
+	tt := []struct {
+		testN string
+
+		d    time.Duration
+		want string
+	}{
+		{"zero", 0, "0ns"},
+		{"nanoseconds", 850, "850ns"},
+		{"microseconds", 12345, "12.3µs"},
+		{"whole microseconds", 2 * time.Microsecond, "2µs"},
+		{"milliseconds", 350 * time.Millisecond, "350ms"},
+		{"fractional milliseconds", 1250 * time.Microsecond, "1.3ms"},
+		{"seconds", 1200 * time.Millisecond, "1.2s"},
+		{"whole seconds", 2 * time.Second, "2s"},
+		{"minutes", 2*time.Minute + 3*time.Second, "2m3s"},
+		{"whole minutes", 2 * time.Minute, "2m"},
+		{"minutes rounded", 2*time.Minute + 3600*time.Millisecond, "2m4s"},
+		{"hours", time.Hour + 5*time.Minute, "1h5m"},
+		{"whole hours", time.Hour, "1h"},
+		{"days", 51 * time.Hour, "2d3h"},
+		{"whole days", 48 * time.Hour, "2d"},
+		{"round up to ms", 999960 * time.Nanosecond, "1ms"},
+		{"round up to s", 999960 * time.Microsecond, "1s"},
+		{"round up to m", 59960 * time.Millisecond, "1m"},
+		{"round up to h", time.Hour - 400*time.Millisecond, "1h"},
+		{"round up to d", 24*time.Hour - 20*time.Second, "1d"},
+		{"negative", -1200 * time.Millisecond, "-1.2s"},
+		{"minimum", math.MinInt64, "-106751d23h"},
+		{"maximum", math.MaxInt64, "106751d23h"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testN, func(t *testing.T) {
+			// --- When ---
+			have := FormatDuration(tc.d)
+
+			// --- Then ---
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
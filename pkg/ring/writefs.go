@@ -0,0 +1,240 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// WritableFile is a file opened for writing by [WritableFS].
+type WritableFile interface {
+	fs.File
+	io.Writer
+}
+
+// WritableFS is a hierarchical filesystem which can be modified. Like for
+// [fs.FS], names are slash-separated paths relative to the filesystem root,
+// see [fs.ValidPath]. Methods return [*fs.PathError] or [*os.LinkError]
+// errors wrapping [fs.ErrInvalid] for invalid names, [fs.ErrNotExist],
+// [fs.ErrExist] or [fs.ErrPermission] as appropriate.
+type WritableFS interface {
+	fs.FS
+
+	// Create creates or truncates the named file and opens it for writing.
+	// New files are created with 0666 permissions (before umask).
+	Create(name string) (WritableFile, error)
+
+	// WriteFile writes data to the named file, creating it with the given
+	// permissions (before umask) if necessary, or truncating it.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+
+	// Mkdir creates the named directory with the given permissions (before
+	// umask). The parent directory must exist.
+	Mkdir(name string, perm fs.FileMode) error
+
+	// MkdirAll creates the named directory and any missing parents with the
+	// given permissions (before umask). Existing directories are not
+	// changed.
+	MkdirAll(name string, perm fs.FileMode) error
+
+	// Remove removes the named file or empty directory.
+	Remove(name string) error
+
+	// RemoveAll removes the named file or directory and any children it
+	// contains. It returns nil when the name does not exist.
+	RemoveAll(name string) error
+
+	// Rename renames (moves) oldname to newname, replacing newname if it is
+	// an existing file.
+	Rename(oldname, newname string) error
+
+	// Chmod changes permissions of the named file.
+	Chmod(name string, mode fs.FileMode) error
+
+	// Symlink creates newname as a symbolic link to oldname. The oldname is
+	// the content of the link and is not validated.
+	Symlink(oldname, newname string) error
+}
+
+// Compile time checks.
+var (
+	_ WritableFS    = &RootFS{}
+	_ fs.StatFS     = &RootFS{}
+	_ fs.ReadFileFS = &RootFS{}
+	_ fs.ReadDirFS  = &RootFS{}
+	_ fs.ReadLinkFS = &RootFS{}
+	_ io.Closer     = &RootFS{}
+	_ WritableFile  = &os.File{}
+)
+
+// RootFS is a [WritableFS] backed by the operating system filesystem rooted
+// at a directory, see [os.Root]. Files outside the directory cannot be
+// accessed, also through symbolic links.
+//
+// It is safe for concurrent use.
+type RootFS struct {
+	root *os.Root // Root directory.
+	fsys fs.FS    // Read-only view of the root directory.
+}
+
+// OpenRootFS returns a new [RootFS] rooted at the directory. The directory
+// must exist. Close the returned filesystem when no longer needed.
+func OpenRootFS(dir string) (*RootFS, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &RootFS{root: root, fsys: root.FS()}, nil
+}
+
+// Name returns the name of the root directory as passed to [OpenRootFS].
+func (rfs *RootFS) Name() string { return rfs.root.Name() }
+
+// Close closes the root directory. Files opened before remain open.
+func (rfs *RootFS) Close() error { return rfs.root.Close() }
+
+// Open opens the named file for reading, see [fs.FS].
+func (rfs *RootFS) Open(name string) (fs.File, error) {
+	return rfs.fsys.Open(name)
+}
+
+// Stat returns a [fs.FileInfo] describing the named file, see [fs.StatFS].
+func (rfs *RootFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(rfs.fsys, name)
+}
+
+// ReadFile reads the named file, see [fs.ReadFileFS].
+func (rfs *RootFS) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(rfs.fsys, name)
+}
+
+// ReadDir reads the named directory returning entries sorted by name, see
+// [fs.ReadDirFS].
+func (rfs *RootFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(rfs.fsys, name)
+}
+
+// ReadLink returns the destination of the named symbolic link, see
+// [fs.ReadLinkFS].
+func (rfs *RootFS) ReadLink(name string) (string, error) {
+	return fs.ReadLink(rfs.fsys, name)
+}
+
+// Lstat returns a [fs.FileInfo] describing the named file without following
+// symbolic links, see [fs.ReadLinkFS].
+func (rfs *RootFS) Lstat(name string) (fs.FileInfo, error) {
+	return fs.Lstat(rfs.fsys, name)
+}
+
+// Create implements [WritableFS].
+func (rfs *RootFS) Create(name string) (WritableFile, error) {
+	pth, err := osPath("create", name)
+	if err != nil {
+		return nil, err
+	}
+	fil, err := rfs.root.Create(pth)
+	if err != nil {
+		return nil, err
+	}
+	return fil, nil
+}
+
+// WriteFile implements [WritableFS].
+func (rfs *RootFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	pth, err := osPath("writefile", name)
+	if err != nil {
+		return err
+	}
+	return rfs.root.WriteFile(pth, data, perm)
+}
+
+// Mkdir implements [WritableFS].
+func (rfs *RootFS) Mkdir(name string, perm fs.FileMode) error {
+	pth, err := osPath("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return rfs.root.Mkdir(pth, perm)
+}
+
+// MkdirAll implements [WritableFS].
+func (rfs *RootFS) MkdirAll(name string, perm fs.FileMode) error {
+	pth, err := osPath("mkdirall", name)
+	if err != nil {
+		return err
+	}
+	return rfs.root.MkdirAll(pth, perm)
+}
+
+// Remove implements [WritableFS].
+func (rfs *RootFS) Remove(name string) error {
+	pth, err := osPath("remove", name)
+	if err != nil {
+		return err
+	}
+	return rfs.root.Remove(pth)
+}
+
+// RemoveAll implements [WritableFS].
+func (rfs *RootFS) RemoveAll(name string) error {
+	pth, err := osPath("removeall", name)
+	if err != nil {
+		return err
+	}
+	return rfs.root.RemoveAll(pth)
+}
+
+// Rename implements [WritableFS].
+func (rfs *RootFS) Rename(oldname, newname string) error {
+	if !fs.ValidPath(oldname) || !fs.ValidPath(newname) {
+		return &os.LinkError{
+			Op:  "rename",
+			Old: oldname,
+			New: newname,
+			Err: fs.ErrInvalid,
+		}
+	}
+	return rfs.root.Rename(
+		filepath.FromSlash(oldname),
+		filepath.FromSlash(newname),
+	)
+}
+
+// Chmod implements [WritableFS].
+func (rfs *RootFS) Chmod(name string, mode fs.FileMode) error {
+	pth, err := osPath("chmod", name)
+	if err != nil {
+		return err
+	}
+	return rfs.root.Chmod(pth, mode)
+}
+
+// Symlink implements [WritableFS].
+func (rfs *RootFS) Symlink(oldname, newname string) error {
+	if !fs.ValidPath(newname) {
+		return &os.LinkError{
+			Op:  "symlink",
+			Old: oldname,
+			New: newname,
+			Err: fs.ErrInvalid,
+		}
+	}
+	return rfs.root.Symlink(
+		filepath.FromSlash(oldname),
+		filepath.FromSlash(newname),
+	)
+}
+
+// osPath returns the operating system path for the slash-separated name. It
+// returns an error wrapping [fs.ErrInvalid] when the name is not valid, see
+// [fs.ValidPath].
+func osPath(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.FromSlash(name), nil
+}
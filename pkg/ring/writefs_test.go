@@ -0,0 +1,472 @@
+// SPDX-FileCopyrightText: (c) 2025 Rafal Zajac <rzajac@gmail.com>
+// SPDX-License-Identifier: MIT
+
+package ring
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ctx42/testing/pkg/assert"
+	"github.com/ctx42/testing/pkg/must"
+)
+
+// rootFS returns a [RootFS] rooted at a new temporary directory and the
+// directory path.
+func rootFS(t *testing.T) (*RootFS, string) {
+	t.Helper()
+	dir := t.TempDir()
+	rfs := must.Value(OpenRootFS(dir))
+	t.Cleanup(func() { _ = rfs.Close() })
+	return rfs, dir
+}
+
+func Test_OpenRootFS(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// --- Given ---
+		dir := t.TempDir()
+
+		// --- When ---
+		have, err := OpenRootFS(dir)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, dir, have.Name())
+		assert.NoError(t, have.Close())
+	})
+
+	t.Run("error - not existing", func(t *testing.T) {
+		// --- Given ---
+		dir := filepath.Join(t.TempDir(), "missing")
+
+		// --- When ---
+		have, err := OpenRootFS(dir)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+		assert.Nil(t, have)
+	})
+}
+
+func Test_RootFS_read(t *testing.T) {
+	// --- Given ---
+	rfs, dir := rootFS(t)
+	must.Nil(os.Mkdir(filepath.Join(dir, "d"), 0o700))
+	must.Nil(os.WriteFile(filepath.Join(dir, "d/f.txt"), []byte("abc"), 0o600))
+	must.Nil(os.Symlink("d/f.txt", filepath.Join(dir, "link")))
+
+	// --- When ---
+	err := fstest.TestFS(rfs, "d/f.txt", "link")
+
+	// --- Then ---
+	assert.NoError(t, err)
+}
+
+func Test_RootFS_Stat(t *testing.T) {
+	// --- Given ---
+	rfs, dir := rootFS(t)
+	must.Nil(os.WriteFile(filepath.Join(dir, "f.txt"), []byte("abc"), 0o600))
+
+	// --- When ---
+	have, err := rfs.Stat("f.txt")
+
+	// --- Then ---
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), have.Size())
+}
+
+func Test_RootFS_ReadLink(t *testing.T) {
+	// --- Given ---
+	rfs, dir := rootFS(t)
+	must.Nil(os.WriteFile(filepath.Join(dir, "f.txt"), []byte("abc"), 0o600))
+	must.Nil(os.Symlink("f.txt", filepath.Join(dir, "link")))
+
+	// --- When ---
+	have, err := rfs.ReadLink("link")
+
+	// --- Then ---
+	assert.NoError(t, err)
+	assert.Equal(t, "f.txt", have)
+	info := must.Value(rfs.Lstat("link"))
+	assert.Equal(t, fs.ModeSymlink, info.Mode().Type())
+}
+
+func Test_RootFS_Create(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		// --- Given ---
+		rfs, dir := rootFS(t)
+
+		// --- When ---
+		fil, err := rfs.Create("f.txt")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		must.Value(fil.Write([]byte("abc")))
+		assert.NoError(t, fil.Close())
+		have := must.Value(os.ReadFile(filepath.Join(dir, "f.txt")))
+		assert.Equal(t, "abc", string(have))
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		// --- Given ---
+		rfs, dir := rootFS(t)
+		pth := filepath.Join(dir, "f.txt")
+		must.Nil(os.WriteFile(pth, []byte("abc"), 0o600))
+
+		// --- When ---
+		fil, err := rfs.Create("f.txt")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.NoError(t, fil.Close())
+		assert.Equal(t, "", string(must.Value(os.ReadFile(pth))))
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		fil, err := rfs.Create("../f.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+		assert.ErrorEqual(t, "create ../f.txt: invalid argument", err)
+		assert.Nil(t, fil)
+	})
+
+	t.Run("error - missing directory", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		fil, err := rfs.Create("d/f.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+		assert.Nil(t, fil)
+	})
+}
+
+func Test_RootFS_WriteFile(t *testing.T) {
+	t.Run("write", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.WriteFile("f.txt", []byte("abc"), 0o600)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", string(must.Value(rfs.ReadFile("f.txt"))))
+		if runtime.GOOS != "windows" {
+			info := must.Value(rfs.Stat("f.txt"))
+			assert.Equal(t, fs.FileMode(0o600), info.Mode().Perm())
+		}
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.WriteFile("/f.txt", nil, 0o600)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+	})
+
+	t.Run("error - escaping symlink", func(t *testing.T) {
+		// --- Given ---
+		rfs, dir := rootFS(t)
+		must.Nil(os.Symlink(t.TempDir(), filepath.Join(dir, "out")))
+
+		// --- When ---
+		err := rfs.WriteFile("out/f.txt", []byte("abc"), 0o600)
+
+		// --- Then ---
+		assert.Error(t, err)
+	})
+}
+
+func Test_RootFS_Mkdir(t *testing.T) {
+	t.Run("mkdir", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.Mkdir("d", 0o700)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.True(t, must.Value(rfs.Stat("d")).IsDir())
+	})
+
+	t.Run("error - exists", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+		must.Nil(rfs.Mkdir("d", 0o700))
+
+		// --- When ---
+		err := rfs.Mkdir("d", 0o700)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrExist, err)
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.Mkdir("d/", 0o700)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+	})
+}
+
+func Test_RootFS_MkdirAll(t *testing.T) {
+	t.Run("mkdir all", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.MkdirAll("a/b/c", 0o700)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.True(t, must.Value(rfs.Stat("a/b/c")).IsDir())
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.MkdirAll("a/../../b", 0o700)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+	})
+}
+
+func Test_RootFS_Remove(t *testing.T) {
+	t.Run("remove", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+		must.Nil(rfs.WriteFile("f.txt", nil, 0o600))
+
+		// --- When ---
+		err := rfs.Remove("f.txt")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		_, err = rfs.Stat("f.txt")
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+	})
+
+	t.Run("error - not empty directory", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+		must.Nil(rfs.MkdirAll("a/b", 0o700))
+
+		// --- When ---
+		err := rfs.Remove("a")
+
+		// --- Then ---
+		assert.Error(t, err)
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.Remove("")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+	})
+}
+
+func Test_RootFS_RemoveAll(t *testing.T) {
+	t.Run("remove all", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+		must.Nil(rfs.MkdirAll("a/b", 0o700))
+		must.Nil(rfs.WriteFile("a/b/f.txt", nil, 0o600))
+
+		// --- When ---
+		err := rfs.RemoveAll("a")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		_, err = rfs.Stat("a")
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+	})
+
+	t.Run("not existing", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.RemoveAll("a")
+
+		// --- Then ---
+		assert.NoError(t, err)
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.RemoveAll("..")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+	})
+}
+
+func Test_RootFS_Rename(t *testing.T) {
+	t.Run("rename", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+		must.Nil(rfs.Mkdir("d", 0o700))
+		must.Nil(rfs.WriteFile("f.txt", []byte("abc"), 0o600))
+
+		// --- When ---
+		err := rfs.Rename("f.txt", "d/g.txt")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", string(must.Value(rfs.ReadFile("d/g.txt"))))
+		_, err = rfs.Stat("f.txt")
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+	})
+
+	t.Run("error - invalid old name", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.Rename("../f.txt", "g.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+		wMsg := "rename ../f.txt g.txt: invalid argument"
+		assert.ErrorEqual(t, wMsg, err)
+	})
+
+	t.Run("error - invalid new name", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+		must.Nil(rfs.WriteFile("f.txt", nil, 0o600))
+
+		// --- When ---
+		err := rfs.Rename("f.txt", "../g.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+	})
+}
+
+func Test_RootFS_Chmod(t *testing.T) {
+	t.Run("chmod", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("permissions are not supported")
+		}
+
+		// --- Given ---
+		rfs, _ := rootFS(t)
+		must.Nil(rfs.WriteFile("f.txt", nil, 0o600))
+
+		// --- When ---
+		err := rfs.Chmod("f.txt", 0o640)
+
+		// --- Then ---
+		assert.NoError(t, err)
+		info := must.Value(rfs.Stat("f.txt"))
+		assert.Equal(t, fs.FileMode(0o640), info.Mode().Perm())
+	})
+
+	t.Run("error - not existing", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.Chmod("f.txt", 0o640)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrNotExist, err)
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.Chmod("./f.txt", 0o640)
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+	})
+}
+
+func Test_RootFS_Symlink(t *testing.T) {
+	t.Run("symlink", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+		must.Nil(rfs.Mkdir("d", 0o700))
+		must.Nil(rfs.WriteFile("d/f.txt", []byte("abc"), 0o600))
+
+		// --- When ---
+		err := rfs.Symlink("d/f.txt", "link")
+
+		// --- Then ---
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", string(must.Value(rfs.ReadFile("link"))))
+	})
+
+	t.Run("error - exists", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+		must.Nil(rfs.WriteFile("f.txt", nil, 0o600))
+
+		// --- When ---
+		err := rfs.Symlink("g.txt", "f.txt")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrExist, err)
+	})
+
+	t.Run("error - invalid name", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+
+		// --- When ---
+		err := rfs.Symlink("f.txt", "../link")
+
+		// --- Then ---
+		assert.ErrorIs(t, fs.ErrInvalid, err)
+		wMsg := "symlink f.txt ../link: invalid argument"
+		assert.ErrorEqual(t, wMsg, err)
+	})
+
+	t.Run("escaping link is not followed", func(t *testing.T) {
+		// --- Given ---
+		rfs, _ := rootFS(t)
+		must.Nil(rfs.Symlink("../outside", "link"))
+
+		// --- When ---
+		_, err := rfs.ReadFile("link")
+
+		// --- Then ---
+		assert.Error(t, err)
+	})
+}